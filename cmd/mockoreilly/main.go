@@ -0,0 +1,30 @@
+// Command mockoreilly serves the fake O'Reilly API from the
+// oreillytest package as a standalone process, for exercising the full
+// CLI and TUI without credentials:
+//
+//	mockoreilly -addr :9901 &
+//	GOREILLY_BASE_URL=http://localhost:9901 GOREILLY_JWT=mock goreilly search go
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/tuannvm/koreilly/internal/oreillytest"
+)
+
+func main() {
+	addr := flag.String("addr", ":9901", "listen address")
+	flag.Parse()
+
+	log.Printf("mockoreilly listening on %s (GOREILLY_BASE_URL=http://localhost%s)", *addr, *addr)
+	log.Fatal(http.ListenAndServe(*addr, logRequests(oreillytest.Handler())))
+}
+
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log.Printf("%s %s", r.Method, r.URL.Path)
+		next.ServeHTTP(w, r)
+	})
+}
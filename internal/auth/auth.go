@@ -0,0 +1,126 @@
+// Package auth manages the O'Reilly session token (orm-jwt) used to
+// authenticate API requests.
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/tuannvm/koreilly/internal/config"
+)
+
+// envToken is checked before the on-disk token.
+const envToken = "GOREILLY_JWT"
+
+// Token is the persisted session credential.
+type Token struct {
+	JWT       string    `json:"jwt"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Service loads, stores, and clears the session token.
+type Service struct {
+	dir string
+
+	// FlagJWT is a token supplied on the command line for this run
+	// only; it outranks every stored credential.
+	FlagJWT string
+
+	// reloginOnce limits the automatic re-login after a 401 to one
+	// attempt per process; reloginJWT holds its result.
+	reloginOnce sync.Once
+	reloginJWT  string
+}
+
+// NewService returns a Service storing tokens under the config dir.
+func NewService() *Service {
+	return &Service{dir: config.Dir()}
+}
+
+func (s *Service) tokenPath() string {
+	return filepath.Join(s.dir, "token.json")
+}
+
+// Token returns the current JWT, following the precedence Resolve
+// documents: flag, environment, keyring, token.json, cookie jar.
+func (s *Service) Token() (string, error) {
+	jwt, _, err := s.Resolve()
+	return jwt, err
+}
+
+// Refresh is consulted after the API rejects the current token. When
+// GOREILLY_EMAIL and GOREILLY_PASSWORD are set it re-runs the email
+// login once per process and stores the fresh token; otherwise it
+// re-reads the stored token, which picks up a newer one written by a
+// login elsewhere. Returning an unchanged token tells the caller no
+// retry is worthwhile.
+func (s *Service) Refresh(ctx context.Context) (string, error) {
+	if jwt := s.relogin(ctx); jwt != "" {
+		return jwt, nil
+	}
+	return s.Token()
+}
+
+// relogin re-authenticates with the credentials in the environment, if
+// any. It never runs when the token was pinned by flag or GOREILLY_JWT,
+// and returns "" when credentials are missing or the attempt failed so
+// Refresh falls back to the stored token.
+func (s *Service) relogin(ctx context.Context) string {
+	if s.FlagJWT != "" || os.Getenv(envToken) != "" {
+		return ""
+	}
+	email, password := os.Getenv("GOREILLY_EMAIL"), os.Getenv("GOREILLY_PASSWORD")
+	if email == "" || password == "" {
+		return ""
+	}
+	s.reloginOnce.Do(func() {
+		tok, err := s.Authenticate(ctx, email, password)
+		if err != nil {
+			// 2FA accounts land here too: the challenge cannot be
+			// answered mid-request, so the original 401 stands.
+			slog.Warn("automatic re-login failed", "err", err)
+			return
+		}
+		if err := s.StoreToken(*tok); err != nil {
+			slog.Warn("re-login succeeded but token not stored", "err", err)
+		}
+		s.reloginJWT = tok.JWT
+	})
+	return s.reloginJWT
+}
+
+// IsAuthenticated reports whether a usable token is available.
+func (s *Service) IsAuthenticated() bool {
+	tok, err := s.Token()
+	return err == nil && tok != ""
+}
+
+// SaveToken persists the token to disk with owner-only permissions.
+func (s *Service) SaveToken(tok Token) error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("creating config dir: %w", err)
+	}
+	data, err := json.MarshalIndent(tok, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(s.tokenPath(), data, 0o600); err != nil {
+		return fmt.Errorf("writing token: %w", err)
+	}
+	return nil
+}
+
+// ClearToken removes the stored token, if any.
+func (s *Service) ClearToken() error {
+	err := os.Remove(s.tokenPath())
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing token: %w", err)
+	}
+	return nil
+}
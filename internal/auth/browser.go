@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// handoffPage is served at the handoff URL. It walks the user through
+// logging in on the O'Reilly site, then the bookmarklet redirects the
+// O'Reilly tab to the local /token callback with the orm-jwt cookie —
+// one click, no password or clipboard handling, and captcha/2FA
+// challenges happen in the real browser where they work. The manual
+// paste form stays as a fallback for locked-down browsers.
+const handoffPage = `<!DOCTYPE html>
+<html>
+<head><title>goreilly login</title></head>
+<body>
+<h1>goreilly browser handoff</h1>
+<ol>
+<li>Open <a href="https://learning.oreilly.com" target="_blank">learning.oreilly.com</a> and log in (2FA and captcha work normally there).</li>
+<li>Drag this bookmarklet to your bookmarks bar, then click it on the O'Reilly tab:
+<a href="javascript:location.href='%s/token?jwt='+encodeURIComponent(document.cookie.split('; ').find(c=>c.startsWith('orm-jwt='))?.slice(8)||'')">send session to goreilly</a></li>
+</ol>
+<p>Or paste the orm-jwt cookie value by hand:</p>
+<form method="POST" action="/token">
+<input type="password" name="jwt" size="60" placeholder="orm-jwt value" autofocus>
+<button type="submit">Send to goreilly</button>
+</form>
+</body>
+</html>`
+
+// BrowserHandoff serves a temporary local page that helps the user
+// extract the orm-jwt from a logged-in browser session and hand it to
+// the CLI — a middle ground between a cookie-file export and parsing
+// the browser's cookie database.
+type BrowserHandoff struct {
+	// Addr is the listen address; empty picks a free localhost port.
+	Addr string
+	// Prompt receives the URL to visit; nil prints nothing.
+	Prompt func(url string)
+}
+
+// Name implements Strategy.
+func (b *BrowserHandoff) Name() string { return "browser" }
+
+// Acquire implements Strategy: it serves the handoff page until a token
+// is posted back or ctx is cancelled.
+func (b *BrowserHandoff) Acquire(ctx context.Context) (*Token, error) {
+	addr := b.Addr
+	if addr == "" {
+		addr = "127.0.0.1:0"
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("starting handoff listener: %w", err)
+	}
+	defer ln.Close()
+
+	baseURL := "http://" + ln.Addr().String()
+
+	got := make(chan string, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprintf(w, handoffPage, baseURL)
+	})
+	// /token accepts the bookmarklet's GET redirect (?jwt=...) as well as
+	// the fallback form POST.
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodPost {
+			http.Error(w, "GET or POST only", http.StatusMethodNotAllowed)
+			return
+		}
+		jwt := strings.TrimSpace(r.FormValue("jwt"))
+		if jwt == "" {
+			http.Error(w, "empty token", http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, "Token received — you can close this tab and return to the terminal.")
+		select {
+		case got <- jwt:
+		default:
+		}
+	})
+	srv := &http.Server{Handler: mux}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	url := baseURL + "/"
+	if b.Prompt != nil {
+		b.Prompt(url)
+	}
+	openBrowser(url)
+	// Open the real login page too, so captcha and 2FA run on the
+	// O'Reilly site before the bookmarklet hands the session over.
+	openBrowser("https://learning.oreilly.com/login/")
+
+	select {
+	case jwt := <-got:
+		return &Token{JWT: jwt, ExpiresAt: expiryFor(jwt)}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// openBrowser best-effort opens url in the default browser; the printed
+// URL covers headless setups.
+func openBrowser(url string) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	_ = cmd.Start()
+}
@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TokenInfo is what a local decode of the orm-jwt reveals. The token is
+// not verified — the API does that — only inspected, so expiry and
+// entitlements reflect what the server issued rather than guesses.
+type TokenInfo struct {
+	Subject      string    `json:"subject,omitempty"`
+	Email        string    `json:"email,omitempty"`
+	Issuer       string    `json:"issuer,omitempty"`
+	AccountType  string    `json:"account_type,omitempty"`
+	Entitlements []string  `json:"entitlements,omitempty"`
+	IssuedAt     time.Time `json:"issued_at,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at,omitempty"`
+}
+
+// ParseJWT decodes a JWT's claims without verifying its signature.
+func ParseJWT(jwt string) (*TokenInfo, error) {
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("token is not a JWT (%d segments)", len(parts))
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("decoding JWT claims: %w", err)
+	}
+	var claims struct {
+		Sub          string   `json:"sub"`
+		Email        string   `json:"email"`
+		Iss          string   `json:"iss"`
+		AccountType  string   `json:"account_type"`
+		Entitlements []string `json:"entitlements"`
+		Iat          int64    `json:"iat"`
+		Exp          int64    `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("parsing JWT claims: %w", err)
+	}
+	info := &TokenInfo{
+		Subject:      claims.Sub,
+		Email:        claims.Email,
+		Issuer:       claims.Iss,
+		AccountType:  claims.AccountType,
+		Entitlements: claims.Entitlements,
+	}
+	if claims.Iat > 0 {
+		info.IssuedAt = time.Unix(claims.Iat, 0)
+	}
+	if claims.Exp > 0 {
+		info.ExpiresAt = time.Unix(claims.Exp, 0)
+	}
+	return info, nil
+}
+
+// TokenInfo decodes the currently resolved token.
+func (s *Service) TokenInfo() (*TokenInfo, error) {
+	jwt, err := s.Token()
+	if err != nil {
+		return nil, err
+	}
+	return ParseJWT(jwt)
+}
+
+// expiryFor reads a token's own exp claim so stored expiry matches what
+// the server issued; opaque tokens fall back to the assumed
+// tokenLifetime.
+func expiryFor(jwt string) time.Time {
+	if info, err := ParseJWT(jwt); err == nil && !info.ExpiresAt.IsZero() {
+		return info.ExpiresAt
+	}
+	return time.Now().Add(tokenLifetime)
+}
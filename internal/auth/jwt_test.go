@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+// fakeJWT assembles an unsigned token with the given claims payload.
+func fakeJWT(claims string) string {
+	enc := base64.RawURLEncoding.EncodeToString
+	return enc([]byte(`{"alg":"none"}`)) + "." + enc([]byte(claims)) + ".sig"
+}
+
+func TestParseJWT(t *testing.T) {
+	jwt := fakeJWT(`{"sub":"user-1","email":"me@example.com","account_type":"individual","entitlements":["epubs","videos"],"exp":1767225600}`)
+	info, err := ParseJWT(jwt)
+	if err != nil {
+		t.Fatalf("ParseJWT: %v", err)
+	}
+	if info.Subject != "user-1" || info.Email != "me@example.com" {
+		t.Errorf("identity claims = %q / %q", info.Subject, info.Email)
+	}
+	if info.AccountType != "individual" || len(info.Entitlements) != 2 {
+		t.Errorf("account claims = %q / %v", info.AccountType, info.Entitlements)
+	}
+	if !info.ExpiresAt.Equal(time.Unix(1767225600, 0)) {
+		t.Errorf("ExpiresAt = %v", info.ExpiresAt)
+	}
+}
+
+func TestParseJWTRejectsOpaqueTokens(t *testing.T) {
+	if _, err := ParseJWT("not-a-jwt"); err == nil {
+		t.Fatal("expected an error for a non-JWT token")
+	}
+}
+
+func TestExpiryForFallsBackForOpaqueTokens(t *testing.T) {
+	got := expiryFor("opaque")
+	want := time.Now().Add(tokenLifetime)
+	if got.Before(want.Add(-time.Minute)) || got.After(want.Add(time.Minute)) {
+		t.Errorf("fallback expiry = %v, want about %v", got, want)
+	}
+}
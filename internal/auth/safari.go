@@ -0,0 +1,199 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SafariCookies reads the orm-jwt session cookie straight out of
+// Safari's Cookies.binarycookies store on macOS, so Safari users can log
+// in without installing a cookie-export extension. The file format is
+// undocumented but stable: a "cook" header, a list of pages, and within
+// each page a list of cookie records with offset-addressed strings.
+type SafariCookies struct {
+	// Path overrides the cookie store location; empty probes the
+	// standard Safari locations under the user's home directory.
+	Path string
+}
+
+// Name implements Strategy.
+func (s *SafariCookies) Name() string { return "safari" }
+
+// Acquire implements Strategy.
+func (s *SafariCookies) Acquire(ctx context.Context) (*Token, error) {
+	path := s.Path
+	if path == "" {
+		var err error
+		if path, err = findSafariCookies(); err != nil {
+			return nil, err
+		}
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		// Since macOS Mojave the cookie store sits behind TCC: reading
+		// it needs Full Disk Access, and a plain permission error is
+		// all the OS reports. Spell out the fix.
+		if errors.Is(err, fs.ErrPermission) {
+			return nil, fmt.Errorf("macOS blocked access to %s; grant your terminal Full Disk Access under System Settings > Privacy & Security, then retry", path)
+		}
+		return nil, fmt.Errorf("reading Safari cookies: %w", err)
+	}
+	cookies, err := parseBinaryCookies(raw)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	for _, ck := range cookies {
+		if ck.Name == "orm-jwt" && ck.Value != "" && strings.Contains(ck.Domain, "oreilly") {
+			expires := ck.Expires
+			if expires.Before(time.Now()) {
+				return nil, fmt.Errorf("the orm-jwt cookie in Safari expired %s; log in again in Safari first", expires.Format("2006-01-02 15:04:05"))
+			}
+			return &Token{JWT: ck.Value, ExpiresAt: expires}, nil
+		}
+	}
+	return nil, fmt.Errorf("no orm-jwt cookie found in %s; log in at learning.oreilly.com in Safari first", path)
+}
+
+// findSafariCookies returns the first Safari cookie store that exists,
+// checking the sandboxed container location (macOS 11+) before the
+// legacy one.
+func findSafariCookies() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	candidates := []string{
+		filepath.Join(home, "Library", "Containers", "com.apple.Safari", "Data", "Library", "Cookies", "Cookies.binarycookies"),
+		filepath.Join(home, "Library", "Cookies", "Cookies.binarycookies"),
+	}
+	for _, p := range candidates {
+		if _, err := os.Stat(p); err == nil || errors.Is(err, fs.ErrPermission) {
+			// A permission error still identifies the file; ReadFile
+			// will surface the TCC guidance.
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("no Cookies.binarycookies found under %s; is Safari set up on this machine?", filepath.Join(home, "Library"))
+}
+
+// binaryCookie is one record decoded from a binarycookies store.
+type binaryCookie struct {
+	Domain  string
+	Name    string
+	Path    string
+	Value   string
+	Expires time.Time
+}
+
+// macEpoch is the reference date binarycookies timestamps count from.
+var macEpoch = time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// parseBinaryCookies decodes a Cookies.binarycookies blob. The layout:
+// "cook" magic, big-endian page count and page sizes, then pages; each
+// page holds a little-endian cookie count, per-cookie offsets, and
+// records whose strings are NUL-terminated at offsets relative to the
+// record start.
+func parseBinaryCookies(data []byte) ([]binaryCookie, error) {
+	if len(data) < 8 || string(data[:4]) != "cook" {
+		return nil, fmt.Errorf("not a binarycookies file")
+	}
+	numPages := int(binary.BigEndian.Uint32(data[4:8]))
+	if len(data) < 8+4*numPages {
+		return nil, fmt.Errorf("truncated page table")
+	}
+	var cookies []binaryCookie
+	off := 8 + 4*numPages
+	for i := 0; i < numPages; i++ {
+		size := int(binary.BigEndian.Uint32(data[8+4*i : 12+4*i]))
+		if off+size > len(data) {
+			return nil, fmt.Errorf("page %d overruns the file", i)
+		}
+		page, err := parseCookiePage(data[off : off+size])
+		if err != nil {
+			return nil, fmt.Errorf("page %d: %w", i, err)
+		}
+		cookies = append(cookies, page...)
+		off += size
+	}
+	return cookies, nil
+}
+
+func parseCookiePage(page []byte) ([]binaryCookie, error) {
+	if len(page) < 8 {
+		return nil, fmt.Errorf("truncated page header")
+	}
+	n := int(binary.LittleEndian.Uint32(page[4:8]))
+	if len(page) < 8+4*n {
+		return nil, fmt.Errorf("truncated cookie offsets")
+	}
+	cookies := make([]binaryCookie, 0, n)
+	for i := 0; i < n; i++ {
+		off := int(binary.LittleEndian.Uint32(page[8+4*i : 12+4*i]))
+		if off < 0 || off >= len(page) {
+			return nil, fmt.Errorf("cookie %d offset out of range", i)
+		}
+		ck, err := parseCookieRecord(page[off:])
+		if err != nil {
+			return nil, fmt.Errorf("cookie %d: %w", i, err)
+		}
+		cookies = append(cookies, ck)
+	}
+	return cookies, nil
+}
+
+func parseCookieRecord(rec []byte) (binaryCookie, error) {
+	if len(rec) < 56 {
+		return binaryCookie{}, fmt.Errorf("truncated record")
+	}
+	size := int(binary.LittleEndian.Uint32(rec[0:4]))
+	if size < 56 || size > len(rec) {
+		return binaryCookie{}, fmt.Errorf("bad record size %d", size)
+	}
+	rec = rec[:size]
+	domain, err := cookieString(rec, binary.LittleEndian.Uint32(rec[16:20]))
+	if err != nil {
+		return binaryCookie{}, err
+	}
+	name, err := cookieString(rec, binary.LittleEndian.Uint32(rec[20:24]))
+	if err != nil {
+		return binaryCookie{}, err
+	}
+	path, err := cookieString(rec, binary.LittleEndian.Uint32(rec[24:28]))
+	if err != nil {
+		return binaryCookie{}, err
+	}
+	value, err := cookieString(rec, binary.LittleEndian.Uint32(rec[28:32]))
+	if err != nil {
+		return binaryCookie{}, err
+	}
+	expiry := math.Float64frombits(binary.LittleEndian.Uint64(rec[40:48]))
+	return binaryCookie{
+		Domain:  strings.TrimPrefix(domain, "."),
+		Name:    name,
+		Path:    path,
+		Value:   value,
+		Expires: macEpoch.Add(time.Duration(expiry) * time.Second),
+	}, nil
+}
+
+// cookieString reads the NUL-terminated string at off within the record.
+func cookieString(rec []byte, off uint32) (string, error) {
+	if int(off) >= len(rec) {
+		return "", fmt.Errorf("string offset %d out of range", off)
+	}
+	s := rec[off:]
+	end := bytes.IndexByte(s, 0)
+	if end < 0 {
+		return "", fmt.Errorf("unterminated string at %d", off)
+	}
+	return string(s[:end]), nil
+}
@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+)
+
+// buildBinaryCookies assembles a minimal single-page store holding the
+// given cookies, mirroring the layout Safari writes.
+func buildBinaryCookies(t *testing.T, cookies []binaryCookie) []byte {
+	t.Helper()
+
+	var records [][]byte
+	for _, ck := range cookies {
+		strs := ck.Domain + "\x00" + ck.Name + "\x00" + ck.Path + "\x00" + ck.Value + "\x00"
+		rec := make([]byte, 56+len(strs))
+		binary.LittleEndian.PutUint32(rec[0:4], uint32(len(rec)))
+		off := uint32(56)
+		binary.LittleEndian.PutUint32(rec[16:20], off)
+		off += uint32(len(ck.Domain)) + 1
+		binary.LittleEndian.PutUint32(rec[20:24], off)
+		off += uint32(len(ck.Name)) + 1
+		binary.LittleEndian.PutUint32(rec[24:28], off)
+		off += uint32(len(ck.Path)) + 1
+		binary.LittleEndian.PutUint32(rec[28:32], off)
+		binary.LittleEndian.PutUint64(rec[40:48], math.Float64bits(ck.Expires.Sub(macEpoch).Seconds()))
+		copy(rec[56:], strs)
+		records = append(records, rec)
+	}
+
+	var page bytes.Buffer
+	page.Write([]byte{0x00, 0x00, 0x01, 0x00})
+	binary.Write(&page, binary.LittleEndian, uint32(len(records)))
+	off := 8 + 4*len(records) + 4
+	for _, rec := range records {
+		binary.Write(&page, binary.LittleEndian, uint32(off))
+		off += len(rec)
+	}
+	binary.Write(&page, binary.LittleEndian, uint32(0))
+	for _, rec := range records {
+		page.Write(rec)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("cook")
+	binary.Write(&out, binary.BigEndian, uint32(1))
+	binary.Write(&out, binary.BigEndian, uint32(page.Len()))
+	out.Write(page.Bytes())
+	return out.Bytes()
+}
+
+func TestParseBinaryCookies(t *testing.T) {
+	expires := time.Date(2030, 6, 1, 12, 0, 0, 0, time.UTC)
+	data := buildBinaryCookies(t, []binaryCookie{
+		{Domain: ".oreilly.com", Name: "orm-jwt", Path: "/", Value: "tok123", Expires: expires},
+		{Domain: "example.com", Name: "other", Path: "/", Value: "x", Expires: expires},
+	})
+
+	cookies, err := parseBinaryCookies(data)
+	if err != nil {
+		t.Fatalf("parseBinaryCookies: %v", err)
+	}
+	if len(cookies) != 2 {
+		t.Fatalf("got %d cookies, want 2", len(cookies))
+	}
+	got := cookies[0]
+	if got.Domain != "oreilly.com" || got.Name != "orm-jwt" || got.Value != "tok123" {
+		t.Errorf("unexpected cookie %+v", got)
+	}
+	if !got.Expires.Equal(expires) {
+		t.Errorf("expiry = %s, want %s", got.Expires, expires)
+	}
+}
+
+func TestParseBinaryCookiesRejectsGarbage(t *testing.T) {
+	for _, data := range [][]byte{nil, []byte("nope"), []byte("cook\x00\x00\x00\x02")} {
+		if _, err := parseBinaryCookies(data); err == nil {
+			t.Errorf("parseBinaryCookies(%q) succeeded, want error", data)
+		}
+	}
+}
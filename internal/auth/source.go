@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/zalando/go-keyring"
+
+	"github.com/tuannvm/koreilly/internal/config"
+)
+
+// SourceCheck records one probe of the credential precedence chain, for
+// `auth status --explain`.
+type SourceCheck struct {
+	// Source names the credential source, e.g. "env" or "keyring".
+	Source string `json:"source"`
+	// Present reports whether this source held a token.
+	Present bool `json:"present"`
+	// Used marks the source that actually won.
+	Used bool `json:"used"`
+	// Detail says where the probe looked or why it was skipped.
+	Detail string `json:"detail,omitempty"`
+}
+
+// Resolve walks the documented credential precedence — --jwt flag, then
+// GOREILLY_JWT, then the system keyring, then token.json, then the
+// persistent cookie jar — and returns the first token found along with
+// the full probe trail. Interactive login is deliberately last and never
+// triggered here; when nothing is found the error says to run it.
+func (s *Service) Resolve() (string, []SourceCheck, error) {
+	probes := []struct {
+		source string
+		probe  func() (jwt, detail string)
+	}{
+		{"flag", s.probeFlag},
+		{"env", probeEnv},
+		{"keyring", probeKeyring},
+		{"token.json", s.probeTokenFile},
+		{"cookie jar", probeCookieJar},
+	}
+	var (
+		checks []SourceCheck
+		jwt    string
+	)
+	for _, p := range probes {
+		got, detail := p.probe()
+		check := SourceCheck{Source: p.source, Present: got != "", Detail: detail}
+		if got != "" && jwt == "" {
+			check.Used = true
+			jwt = got
+		}
+		checks = append(checks, check)
+	}
+	if jwt == "" {
+		return "", checks, fmt.Errorf("no session token found (run goreilly login, or set %s)", envToken)
+	}
+	return jwt, checks, nil
+}
+
+func (s *Service) probeFlag() (string, string) {
+	if s.FlagJWT == "" {
+		return "", "--jwt not set"
+	}
+	return s.FlagJWT, "--jwt"
+}
+
+func probeEnv() (string, string) {
+	return os.Getenv(envToken), envToken
+}
+
+func probeKeyring() (string, string) {
+	detail := keyringService + "/" + keyringUser()
+	jwt, err := keyring.Get(keyringService, keyringUser())
+	if err != nil {
+		if err != keyring.ErrNotFound {
+			detail = fmt.Sprintf("%s (unavailable: %v)", detail, err)
+		}
+		return "", detail
+	}
+	return jwt, detail
+}
+
+func (s *Service) probeTokenFile() (string, string) {
+	path := s.tokenPath()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", path
+	}
+	var tok Token
+	if err := json.Unmarshal(data, &tok); err != nil {
+		return "", fmt.Sprintf("%s (unreadable)", path)
+	}
+	if !tok.ExpiresAt.IsZero() && tok.ExpiresAt.Before(time.Now()) {
+		return tok.JWT, fmt.Sprintf("%s (expired %s)", path, tok.ExpiresAt.Format("2006-01-02 15:04:05"))
+	}
+	return tok.JWT, path
+}
+
+// probeCookieJar looks for a live orm-jwt cookie in the persistent jar
+// the HTTP client keeps — the session left behind by a password login.
+func probeCookieJar() (string, string) {
+	path := config.CookiesPath()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", path
+	}
+	var entries map[string][]*http.Cookie
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return "", fmt.Sprintf("%s (unreadable)", path)
+	}
+	now := time.Now()
+	for host, cookies := range entries {
+		if !strings.Contains(host, "oreilly") {
+			continue
+		}
+		for _, c := range cookies {
+			if c.Name == "orm-jwt" && c.Value != "" && (c.Expires.IsZero() || c.Expires.After(now)) {
+				return c.Value, path
+			}
+		}
+	}
+	return "", path
+}
@@ -0,0 +1,125 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// A Strategy obtains a session token one particular way. New login
+// mechanisms (browser handoff, SSO, cookie imports) implement this
+// instead of growing Service.Authenticate, so each can be selected by
+// flag and tested in isolation.
+type Strategy interface {
+	// Name identifies the strategy in flags and log lines.
+	Name() string
+	// Acquire obtains a fresh session token.
+	Acquire(ctx context.Context) (*Token, error)
+}
+
+// PasswordLogin authenticates against the login endpoint with email and
+// password. It is the default strategy.
+type PasswordLogin struct {
+	Service  *Service
+	Email    string
+	Password string
+	// OTPPrompt asks the user for a one-time code when the account has
+	// 2FA enabled; nil surfaces the MFAChallenge to the caller instead.
+	OTPPrompt func() (string, error)
+}
+
+// Name implements Strategy.
+func (p *PasswordLogin) Name() string { return "password" }
+
+// Acquire implements Strategy.
+func (p *PasswordLogin) Acquire(ctx context.Context) (*Token, error) {
+	tok, err := p.Service.Authenticate(ctx, p.Email, p.Password)
+	var mfa *MFAChallenge
+	if errors.As(err, &mfa) && p.OTPPrompt != nil {
+		code, perr := p.OTPPrompt()
+		if perr != nil {
+			return nil, perr
+		}
+		return p.Service.AuthenticateOTP(ctx, mfa.Token, strings.TrimSpace(code))
+	}
+	return tok, err
+}
+
+// OTPLogin completes a password login that stopped at an MFAChallenge;
+// the TUI uses it once it has collected the code in its own field.
+type OTPLogin struct {
+	Service  *Service
+	MFAToken string
+	Code     string
+}
+
+// Name implements Strategy.
+func (o *OTPLogin) Name() string { return "otp" }
+
+// Acquire implements Strategy.
+func (o *OTPLogin) Acquire(ctx context.Context) (*Token, error) {
+	return o.Service.AuthenticateOTP(ctx, o.MFAToken, strings.TrimSpace(o.Code))
+}
+
+// StaticJWT wraps a token obtained out of band, e.g. copied from the
+// browser's orm-jwt cookie.
+type StaticJWT struct {
+	JWT string
+}
+
+// Name implements Strategy.
+func (s *StaticJWT) Name() string { return "jwt" }
+
+// Acquire implements Strategy.
+func (s *StaticJWT) Acquire(ctx context.Context) (*Token, error) {
+	if s.JWT == "" {
+		return nil, fmt.Errorf("no JWT provided")
+	}
+	return &Token{JWT: s.JWT, ExpiresAt: expiryFor(s.JWT)}, nil
+}
+
+// CookieImport pulls the orm-jwt session cookie out of a JSON cookie
+// export (the format browser extensions like Cookie-Editor produce: an
+// array of {name, value, ...} objects).
+type CookieImport struct {
+	Path string
+}
+
+// Name implements Strategy.
+func (c *CookieImport) Name() string { return "cookies" }
+
+// Acquire implements Strategy.
+func (c *CookieImport) Acquire(ctx context.Context) (*Token, error) {
+	raw, err := os.ReadFile(c.Path)
+	if err != nil {
+		return nil, fmt.Errorf("reading cookie export: %w", err)
+	}
+	var cookies []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal(raw, &cookies); err != nil {
+		return nil, fmt.Errorf("parsing cookie export %s: %w", c.Path, err)
+	}
+	for _, ck := range cookies {
+		if ck.Name == "orm-jwt" && ck.Value != "" {
+			return &Token{JWT: ck.Value, ExpiresAt: expiryFor(ck.Value)}, nil
+		}
+	}
+	return nil, fmt.Errorf("no orm-jwt cookie found in %s", c.Path)
+}
+
+// Login runs a strategy and persists the token it yields.
+func (s *Service) Login(ctx context.Context, strategy Strategy) (*Token, error) {
+	tok, err := strategy.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s login: %w", strategy.Name(), err)
+	}
+	if err := s.StoreToken(*tok); err != nil {
+		return nil, err
+	}
+	return tok, nil
+}
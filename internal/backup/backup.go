@@ -0,0 +1,132 @@
+// Package backup bundles goreilly's config and state into a single
+// archive so a library can move between machines.
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/tuannvm/koreilly/internal/config"
+)
+
+// secretFiles hold credentials and are only archived on request.
+var secretFiles = map[string]bool{
+	"token.json":   true,
+	"cookies.json": true,
+}
+
+// configFiles and dataFiles are what a backup carries, relative to the
+// config and data directories respectively.
+var (
+	configFiles = []string{"config.yaml", "token.json", "cookies.json"}
+	dataFiles   = []string{"catalog.db", "events.jsonl", "bookmarks.json", "progress.json", "plan.json", "snippets.txt"}
+)
+
+// Create writes a gzipped tar of config and state to w. Token and
+// cookie files are skipped unless includeSecrets is set, so backups are
+// safe to park on shared storage by default.
+func Create(w io.Writer, includeSecrets bool) error {
+	gz := gzip.NewWriter(w)
+	defer gz.Close()
+	tw := tar.NewWriter(gz)
+	defer tw.Close()
+
+	add := func(dir, prefix, name string) error {
+		if secretFiles[name] && !includeSecrets {
+			return nil
+		}
+		path := filepath.Join(dir, name)
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil // absent state files are simply not archived
+		}
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = prefix + "/" + name
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	}
+
+	for _, name := range configFiles {
+		if err := add(config.Dir(), "config", name); err != nil {
+			return fmt.Errorf("archiving %s: %w", name, err)
+		}
+	}
+	for _, name := range dataFiles {
+		if err := add(config.DataDir(), "data", name); err != nil {
+			return fmt.Errorf("archiving %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Restore extracts a backup archive into the current config and data
+// directories. Existing files are only replaced when overwrite is set.
+func Restore(r io.Reader, overwrite bool) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("opening backup: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading backup: %w", err)
+		}
+		prefix, name := filepath.Split(hdr.Name)
+		var destDir string
+		switch filepath.Clean(prefix) {
+		case "config":
+			destDir = config.Dir()
+		case "data":
+			destDir = config.DataDir()
+		default:
+			return fmt.Errorf("unexpected entry %q in backup", hdr.Name)
+		}
+		// Entry names come from the archive; keep them to plain files.
+		if name != filepath.Base(name) || name == "" {
+			return fmt.Errorf("unsafe entry name %q in backup", hdr.Name)
+		}
+		dest := filepath.Join(destDir, name)
+		if _, err := os.Stat(dest); err == nil && !overwrite {
+			return fmt.Errorf("%s already exists (use --force to replace)", dest)
+		}
+		if err := os.MkdirAll(destDir, 0o755); err != nil {
+			return err
+		}
+		mode := os.FileMode(hdr.Mode & 0o777)
+		if mode == 0 {
+			mode = 0o644
+		}
+		f, err := os.OpenFile(dest, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(f, tr); err != nil {
+			f.Close()
+			return fmt.Errorf("writing %s: %w", dest, err)
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+}
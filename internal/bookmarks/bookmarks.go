@@ -0,0 +1,123 @@
+// Package bookmarks persists named reading positions so the terminal
+// reader can jump back to them across sessions.
+package bookmarks
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tuannvm/koreilly/internal/config"
+)
+
+// Bookmark is one saved reading position.
+type Bookmark struct {
+	Name       string    `json:"name"`
+	BookID     string    `json:"book_id"`
+	BookTitle  string    `json:"book_title"`
+	Chapter    string    `json:"chapter"`
+	ChapterIdx int       `json:"chapter_idx"`
+	Offset     int       `json:"offset"` // viewport scroll offset in lines
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Store reads and writes the bookmark file.
+type Store struct {
+	path string
+}
+
+// NewStore returns a Store over the default bookmark file.
+func NewStore() *Store {
+	return &Store{path: filepath.Join(config.DataDir(), "bookmarks.json")}
+}
+
+func (s *Store) load() ([]Bookmark, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var list []Bookmark
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", s.path, err)
+	}
+	return list, nil
+}
+
+func (s *Store) save(list []Bookmark) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0o644)
+}
+
+// Add saves a bookmark, replacing any existing one with the same name
+// on the same book.
+func (s *Store) Add(b Bookmark) error {
+	if b.CreatedAt.IsZero() {
+		b.CreatedAt = time.Now()
+	}
+	list, err := s.load()
+	if err != nil {
+		return err
+	}
+	out := list[:0]
+	for _, existing := range list {
+		if existing.BookID == b.BookID && existing.Name == b.Name {
+			continue
+		}
+		out = append(out, existing)
+	}
+	return s.save(append(out, b))
+}
+
+// List returns all bookmarks, newest first.
+func (s *Store) List() ([]Bookmark, error) {
+	list, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(list)-1; i < j; i, j = i+1, j-1 {
+		list[i], list[j] = list[j], list[i]
+	}
+	return list, nil
+}
+
+// ListForBook returns bookmarks for one book, newest first.
+func (s *Store) ListForBook(bookID string) ([]Bookmark, error) {
+	all, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	var out []Bookmark
+	for _, b := range all {
+		if b.BookID == bookID {
+			out = append(out, b)
+		}
+	}
+	return out, nil
+}
+
+// Remove deletes the named bookmark on a book, if present.
+func (s *Store) Remove(bookID, name string) error {
+	list, err := s.load()
+	if err != nil {
+		return err
+	}
+	out := list[:0]
+	for _, b := range list {
+		if b.BookID == bookID && b.Name == name {
+			continue
+		}
+		out = append(out, b)
+	}
+	return s.save(out)
+}
@@ -0,0 +1,47 @@
+package cacheserver
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// Fetch tries to pull a book from a peer cache into destPath. It
+// returns false (without error) when the peer doesn't have the book, so
+// callers can fall through to a normal download.
+func Fetch(ctx context.Context, baseURL, token, id, format, destPath string) (bool, error) {
+	u := fmt.Sprintf("%s/v1/books/%s/%s", baseURL, id, format)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	hc := &http.Client{Timeout: 5 * time.Minute}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("peer cache request: %w", err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusGone:
+		return false, nil
+	default:
+		return false, fmt.Errorf("peer cache returned status %d", resp.StatusCode)
+	}
+
+	f, err := os.Create(destPath)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		os.Remove(destPath)
+		return false, fmt.Errorf("copying from peer cache: %w", err)
+	}
+	return true, nil
+}
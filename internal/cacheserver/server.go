@@ -0,0 +1,81 @@
+// Package cacheserver lets one goreilly instance serve its downloaded
+// books to other instances on the LAN, so a team doesn't re-download
+// the same titles. Peers still authenticate to O'Reilly individually;
+// the cache only shares already-fetched artifacts.
+package cacheserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/tuannvm/koreilly/internal/catalog"
+)
+
+// Server exposes the local catalog over HTTP, guarded by a shared
+// bearer token.
+type Server struct {
+	catalog *catalog.Catalog
+	token   string
+	mux     *http.ServeMux
+}
+
+// New builds a Server over the given catalog. token must be non-empty;
+// requests without it are rejected.
+func New(cat *catalog.Catalog, token string) *Server {
+	s := &Server{catalog: cat, token: token, mux: http.NewServeMux()}
+	s.mux.HandleFunc("/v1/catalog", s.handleCatalog)
+	s.mux.HandleFunc("/v1/books/", s.handleBook)
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	if s.token == "" {
+		return false
+	}
+	return r.Header.Get("Authorization") == "Bearer "+s.token
+}
+
+func (s *Server) handleCatalog(w http.ResponseWriter, r *http.Request) {
+	records, err := s.catalog.List()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(records)
+}
+
+// handleBook serves /v1/books/{id}/{format}.
+func (s *Server) handleBook(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/books/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		http.Error(w, "want /v1/books/{id}/{format}", http.StatusBadRequest)
+		return
+	}
+	rec, err := s.catalog.Get(parts[0], parts[1])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if rec == nil {
+		http.Error(w, "not in cache", http.StatusNotFound)
+		return
+	}
+	if _, err := os.Stat(rec.Path); err != nil {
+		http.Error(w, "cached file missing", http.StatusGone)
+		return
+	}
+	http.ServeFile(w, r, rec.Path)
+}
@@ -0,0 +1,124 @@
+// Package catalog maintains the local database of downloaded books so
+// listings and duplicate checks don't depend on filenames.
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/tuannvm/koreilly/internal/config"
+)
+
+var booksBucket = []byte("books")
+
+// Record is one downloaded work.
+type Record struct {
+	ID           string    `json:"id"`
+	Title        string    `json:"title"`
+	Authors      []string  `json:"authors,omitempty"`
+	Topics       []string  `json:"topics,omitempty"`
+	ISBN         string    `json:"isbn,omitempty"`
+	Path         string    `json:"path"`
+	Format       string    `json:"format"`
+	SHA256       string    `json:"sha256,omitempty"`
+	DownloadedAt time.Time `json:"downloaded_at"`
+}
+
+// Catalog is a bbolt-backed store of Records keyed by id and format.
+type Catalog struct {
+	db *bolt.DB
+}
+
+// DefaultPath is the catalog location inside the config dir.
+func DefaultPath() string {
+	return filepath.Join(config.DataDir(), "catalog.db")
+}
+
+// Open opens (creating if needed) the catalog at path.
+func Open(path string) (*Catalog, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("creating catalog dir: %w", err)
+	}
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 2 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("opening catalog: %w", err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{booksBucket, queueBucket, deliveriesBucket, deliveryPendingBucket, watchSeenBucket, partialBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Catalog{db: db}, nil
+}
+
+// Close releases the database.
+func (c *Catalog) Close() error {
+	return c.db.Close()
+}
+
+func key(id, format string) []byte {
+	return []byte(id + "|" + format)
+}
+
+// Put stores or replaces a record.
+func (c *Catalog) Put(r Record) error {
+	if r.DownloadedAt.IsZero() {
+		r.DownloadedAt = time.Now()
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return c.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(booksBucket).Put(key(r.ID, r.Format), data)
+	})
+}
+
+// Get returns the record for an id/format pair, or nil if absent.
+func (c *Catalog) Get(id, format string) (*Record, error) {
+	var r *Record
+	err := c.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(booksBucket).Get(key(id, format))
+		if data == nil {
+			return nil
+		}
+		r = &Record{}
+		return json.Unmarshal(data, r)
+	})
+	return r, err
+}
+
+// Delete removes a record.
+func (c *Catalog) Delete(id, format string) error {
+	return c.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(booksBucket).Delete(key(id, format))
+	})
+}
+
+// List returns every record in key order.
+func (c *Catalog) List() ([]Record, error) {
+	var records []Record
+	err := c.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(booksBucket).ForEach(func(_, v []byte) error {
+			var r Record
+			if err := json.Unmarshal(v, &r); err != nil {
+				return err
+			}
+			records = append(records, r)
+			return nil
+		})
+	})
+	return records, err
+}
@@ -0,0 +1,49 @@
+package catalog
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestPutGetList(t *testing.T) {
+	c, err := Open(filepath.Join(t.TempDir(), "catalog.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	r := Record{ID: "9781492077213", Title: "Learning Go", Format: "epub", Path: "/books/learning-go.epub"}
+	if err := c.Put(r); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := c.Get("9781492077213", "epub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.Title != "Learning Go" {
+		t.Fatalf("Get returned %+v", got)
+	}
+	if got.DownloadedAt.IsZero() {
+		t.Error("DownloadedAt was not defaulted")
+	}
+
+	if missing, _ := c.Get("9781492077213", "pdf"); missing != nil {
+		t.Errorf("expected nil for missing format, got %+v", missing)
+	}
+
+	records, err := c.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("List returned %d records, want 1", len(records))
+	}
+
+	if err := c.Delete("9781492077213", "epub"); err != nil {
+		t.Fatal(err)
+	}
+	if gone, _ := c.Get("9781492077213", "epub"); gone != nil {
+		t.Errorf("record still present after Delete: %+v", gone)
+	}
+}
@@ -0,0 +1,138 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	deliveriesBucket      = []byte("deliveries")
+	deliveryPendingBucket = []byte("delivery_pending")
+)
+
+// Delivery is one attempt to send a file to a device or address.
+type Delivery struct {
+	BookID string    `json:"book_id,omitempty"`
+	Path   string    `json:"path"`
+	SHA256 string    `json:"sha256,omitempty"`
+	Target string    `json:"target"`
+	SentAt time.Time `json:"sent_at"`
+	OK     bool      `json:"ok"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// DeliveryAppend records one delivery attempt, keyed by its timestamp
+// so history lists chronologically.
+func (c *Catalog) DeliveryAppend(d Delivery) error {
+	if d.SentAt.IsZero() {
+		d.SentAt = time.Now()
+	}
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	return c.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(deliveriesBucket)
+		seq, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+		key := fmt.Sprintf("%d|%06d", d.SentAt.UnixNano(), seq)
+		return b.Put([]byte(key), data)
+	})
+}
+
+// DeliveryList returns every recorded delivery, oldest first.
+func (c *Catalog) DeliveryList() ([]Delivery, error) {
+	var out []Delivery
+	err := c.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(deliveriesBucket).ForEach(func(_, v []byte) error {
+			var d Delivery
+			if err := json.Unmarshal(v, &d); err != nil {
+				return err
+			}
+			out = append(out, d)
+			return nil
+		})
+	})
+	return out, err
+}
+
+// PendingDelivery is a delivery waiting for another attempt after a
+// transient failure. It survives restarts so a flaky SMTP server never
+// loses a book.
+type PendingDelivery struct {
+	BookID      string    `json:"book_id,omitempty"`
+	Path        string    `json:"path"`
+	SHA256      string    `json:"sha256,omitempty"`
+	Target      string    `json:"target"`
+	Attempts    int       `json:"attempts"`
+	NextAttempt time.Time `json:"next_attempt"`
+	LastError   string    `json:"last_error,omitempty"`
+	EnqueuedAt  time.Time `json:"enqueued_at"`
+}
+
+func pendingKey(path, target string) []byte {
+	return []byte(path + "|" + target)
+}
+
+// PendingPut stores or replaces a pending delivery, keyed by file and
+// target so repeated failures update one entry.
+func (c *Catalog) PendingPut(p PendingDelivery) error {
+	if p.EnqueuedAt.IsZero() {
+		p.EnqueuedAt = time.Now()
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return c.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(deliveryPendingBucket).Put(pendingKey(p.Path, p.Target), data)
+	})
+}
+
+// PendingList returns every delivery awaiting a retry.
+func (c *Catalog) PendingList() ([]PendingDelivery, error) {
+	var out []PendingDelivery
+	err := c.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(deliveryPendingBucket).ForEach(func(_, v []byte) error {
+			var p PendingDelivery
+			if err := json.Unmarshal(v, &p); err != nil {
+				return err
+			}
+			out = append(out, p)
+			return nil
+		})
+	})
+	return out, err
+}
+
+// PendingDelete removes a pending delivery once it succeeds or is given
+// up on.
+func (c *Catalog) PendingDelete(path, target string) error {
+	return c.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(deliveryPendingBucket).Delete(pendingKey(path, target))
+	})
+}
+
+// Delivered reports whether an identical file (by checksum) already
+// reached target successfully, which is how send avoids re-emailing the
+// same 40MB attachment.
+func (c *Catalog) Delivered(sha256, target string) (bool, error) {
+	if sha256 == "" {
+		return false, nil
+	}
+	deliveries, err := c.DeliveryList()
+	if err != nil {
+		return false, err
+	}
+	for _, d := range deliveries {
+		if d.OK && d.SHA256 == sha256 && d.Target == target {
+			return true, nil
+		}
+	}
+	return false, nil
+}
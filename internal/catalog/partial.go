@@ -0,0 +1,53 @@
+package catalog
+
+import (
+	"encoding/json"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var partialBucket = []byte("partials")
+
+// Partial tracks an in-flight download's spooled state, so a run after
+// a crash or ctrl-c resumes from the chapters already on disk instead
+// of starting over.
+type Partial struct {
+	ID        string    `json:"id"`
+	Dir       string    `json:"dir"`
+	Chapters  int       `json:"chapters"` // chapters spooled so far
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PartialPut stores or replaces the in-flight state for a book.
+func (c *Catalog) PartialPut(p Partial) error {
+	p.UpdatedAt = time.Now()
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return c.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(partialBucket).Put([]byte(p.ID), data)
+	})
+}
+
+// PartialGet returns the in-flight state for a book, or nil.
+func (c *Catalog) PartialGet(id string) (*Partial, error) {
+	var p *Partial
+	err := c.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(partialBucket).Get([]byte(id))
+		if data == nil {
+			return nil
+		}
+		p = &Partial{}
+		return json.Unmarshal(data, p)
+	})
+	return p, err
+}
+
+// PartialDelete clears the in-flight state once a download completes.
+func (c *Catalog) PartialDelete(id string) error {
+	return c.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(partialBucket).Delete([]byte(id))
+	})
+}
@@ -0,0 +1,84 @@
+package catalog
+
+import (
+	"encoding/json"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var queueBucket = []byte("queue")
+
+// Queue item statuses. Items move queued → running → done/failed, with
+// paused reachable from either of the first two.
+const (
+	QueueQueued  = "queued"
+	QueueRunning = "running"
+	QueuePaused  = "paused"
+	QueueDone    = "done"
+	QueueFailed  = "failed"
+)
+
+// QueueItem is one pending (or finished) download in the persistent
+// queue. Living in the catalog DB, the queue survives restarts.
+type QueueItem struct {
+	ID         string    `json:"id"`
+	Title      string    `json:"title,omitempty"`
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+	EnqueuedAt time.Time `json:"enqueued_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// QueuePut stores or replaces a queue item, stamping its timestamps.
+func (c *Catalog) QueuePut(item QueueItem) error {
+	now := time.Now()
+	if item.EnqueuedAt.IsZero() {
+		item.EnqueuedAt = now
+	}
+	item.UpdatedAt = now
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	return c.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(queueBucket).Put([]byte(item.ID), data)
+	})
+}
+
+// QueueGet returns the queue item for id, or nil if absent.
+func (c *Catalog) QueueGet(id string) (*QueueItem, error) {
+	var item *QueueItem
+	err := c.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(queueBucket).Get([]byte(id))
+		if data == nil {
+			return nil
+		}
+		item = &QueueItem{}
+		return json.Unmarshal(data, item)
+	})
+	return item, err
+}
+
+// QueueList returns every queue item in key order.
+func (c *Catalog) QueueList() ([]QueueItem, error) {
+	var items []QueueItem
+	err := c.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(queueBucket).ForEach(func(_, v []byte) error {
+			var item QueueItem
+			if err := json.Unmarshal(v, &item); err != nil {
+				return err
+			}
+			items = append(items, item)
+			return nil
+		})
+	})
+	return items, err
+}
+
+// QueueDelete removes a queue item.
+func (c *Catalog) QueueDelete(id string) error {
+	return c.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(queueBucket).Delete([]byte(id))
+	})
+}
@@ -0,0 +1,50 @@
+package catalog
+
+import (
+	"encoding/json"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var watchSeenBucket = []byte("watch_seen")
+
+// WatchSeen marks when a watch run first noticed a title, so repeat
+// polls only notify about genuinely new releases.
+type WatchSeen struct {
+	Title  string    `json:"title"`
+	SeenAt time.Time `json:"seen_at"`
+}
+
+// SeenEmpty reports whether no titles have been recorded yet, i.e. the
+// next poll establishes the baseline.
+func (c *Catalog) SeenEmpty() (bool, error) {
+	var empty bool
+	err := c.db.View(func(tx *bolt.Tx) error {
+		k, _ := tx.Bucket(watchSeenBucket).Cursor().First()
+		empty = k == nil
+		return nil
+	})
+	return empty, err
+}
+
+// SeenHas reports whether a watch run already noticed this product.
+func (c *Catalog) SeenHas(id string) (bool, error) {
+	var found bool
+	err := c.db.View(func(tx *bolt.Tx) error {
+		found = tx.Bucket(watchSeenBucket).Get([]byte(id)) != nil
+		return nil
+	})
+	return found, err
+}
+
+// SeenAdd records a product as noticed.
+func (c *Catalog) SeenAdd(id, title string) error {
+	data, err := json.Marshal(WatchSeen{Title: title, SeenAt: time.Now()})
+	if err != nil {
+		return err
+	}
+	return c.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(watchSeenBucket).Put([]byte(id), data)
+	})
+}
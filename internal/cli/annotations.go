@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tuannvm/koreilly/internal/config"
+	"github.com/tuannvm/koreilly/internal/readwise"
+	"github.com/tuannvm/koreilly/internal/services/oreilly"
+)
+
+var annotationsFlags struct {
+	table tableFlags
+}
+
+var annotationsCmd = &cobra.Command{
+	Use:   "annotations",
+	Short: "List and export the highlights on your O'Reilly account",
+}
+
+var annotationsLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List your highlights",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		jwt, err := newAuthService().Token()
+		if err != nil {
+			return err
+		}
+		annotations, err := newOreillyService(cfg).FetchAnnotations(cmd.Context(), jwt)
+		if err != nil {
+			return err
+		}
+		if machineOutput() {
+			return emitStructured(annotations)
+		}
+		if len(annotations) == 0 {
+			fmt.Println("No highlights.")
+			return nil
+		}
+		return renderTable(annotationsFlags.table, annotations, map[string]func(oreilly.Annotation) string{
+			"book":    func(a oreilly.Annotation) string { return a.Book.Title },
+			"chapter": func(a oreilly.Annotation) string { return a.ChapterTitle },
+			"quote":   func(a oreilly.Annotation) string { return a.Quote },
+			"note":    func(a oreilly.Annotation) string { return a.Note },
+		})
+	},
+}
+
+var annotationsReadwiseCmd = &cobra.Command{
+	Use:   "readwise",
+	Short: "Push highlights to Readwise",
+	Long: `Readwise exports every highlight the account has made on the platform
+to Readwise's API, authenticated with the token in the readwise.token
+config key. Exported highlight ids are remembered locally, so running
+the command again only pushes highlights made since the last export.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		if cfg.Readwise.Token == "" {
+			return fmt.Errorf("no Readwise token; set readwise.token with goreilly config set")
+		}
+		jwt, err := newAuthService().Token()
+		if err != nil {
+			return err
+		}
+		annotations, err := newOreillyService(cfg).FetchAnnotations(cmd.Context(), jwt)
+		if err != nil {
+			return err
+		}
+
+		sent, err := readwise.OpenSentLog()
+		if err != nil {
+			return err
+		}
+		var batch []readwise.Highlight
+		var ids []string
+		for _, a := range annotations {
+			// Readwise rejects empty highlight text, and re-sending ids
+			// from the log would duplicate entries on their side.
+			if a.Quote == "" || sent.Has(a.ID) {
+				continue
+			}
+			batch = append(batch, readwise.Highlight{
+				Text:          a.Quote,
+				Title:         a.Book.Title,
+				Category:      "books",
+				SourceType:    "goreilly",
+				SourceURL:     a.ChapterURL,
+				Note:          a.Note,
+				HighlightedAt: a.LastModified,
+			})
+			ids = append(ids, a.ID)
+		}
+		if len(batch) == 0 {
+			fmt.Printf("Nothing new to export (%d highlights already sent).\n", len(annotations))
+			return nil
+		}
+		if err := readwise.Push(cmd.Context(), cfg.Readwise.Token, batch); err != nil {
+			return err
+		}
+		for _, id := range ids {
+			sent.Add(id)
+		}
+		if err := sent.Save(); err != nil {
+			return err
+		}
+		fmt.Printf("Pushed %d highlights to Readwise (%d already sent).\n", len(batch), len(annotations)-len(batch))
+		return nil
+	},
+}
+
+func init() {
+	annotationsFlags.table.addTableFlags(annotationsLsCmd, []string{"book", "chapter", "quote"})
+	annotationsCmd.AddCommand(annotationsLsCmd, annotationsReadwiseCmd)
+	rootCmd.AddCommand(annotationsCmd)
+}
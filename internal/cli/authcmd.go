@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tuannvm/koreilly/internal/auth"
+	"github.com/tuannvm/koreilly/internal/style"
+)
+
+var authStatusFlags struct {
+	explain bool
+}
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Inspect authentication state",
+}
+
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether a session token is available and which source supplied it",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jwt, checks, err := newAuthService().Resolve()
+
+		if machineOutput() {
+			used := ""
+			for _, c := range checks {
+				if c.Used {
+					used = c.Source
+				}
+			}
+			return emitOne(struct {
+				Authenticated bool               `json:"authenticated"`
+				Source        string             `json:"source,omitempty"`
+				Checks        []auth.SourceCheck `json:"checks"`
+			}{jwt != "", used, checks})
+		}
+
+		if err != nil {
+			fmt.Printf("%sNot logged in. Run goreilly login to authenticate.\n", style.Emoji("🔒 "))
+		} else {
+			for _, c := range checks {
+				if c.Used {
+					fmt.Printf("%sLogged in; token supplied by %s (%s).\n", style.Emoji("🔓 "), c.Source, c.Detail)
+				}
+			}
+		}
+		if authStatusFlags.explain {
+			fmt.Println("\nCredential sources, highest precedence first:")
+			for _, c := range checks {
+				state := "not found"
+				switch {
+				case c.Used:
+					state = "found, used"
+				case c.Present:
+					state = "found, outranked"
+				}
+				fmt.Printf("  %-11s %s — %s\n", c.Source, state, c.Detail)
+			}
+			fmt.Println("  interactive last resort: goreilly login")
+		}
+		return nil
+	},
+}
+
+func init() {
+	authStatusCmd.Flags().BoolVar(&authStatusFlags.explain, "explain", false, "list every credential source and which one won")
+	authCmd.AddCommand(authStatusCmd)
+	rootCmd.AddCommand(authCmd)
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tuannvm/koreilly/internal/backup"
+	"github.com/tuannvm/koreilly/internal/style"
+)
+
+var backupFlags struct {
+	output         string
+	includeSecrets bool
+	force          bool
+}
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Back up or restore config and library state",
+}
+
+var backupCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Bundle config, catalog, and bookmarks into an archive",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		f, err := os.Create(backupFlags.output)
+		if err != nil {
+			return err
+		}
+		if err := backup.Create(f, backupFlags.includeSecrets); err != nil {
+			f.Close()
+			os.Remove(backupFlags.output)
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		fmt.Printf("%sWrote %s\n", style.Emoji("📦 "), backupFlags.output)
+		if !backupFlags.includeSecrets {
+			fmt.Println("Token and cookies were left out; pass --include-secrets to carry them.")
+		}
+		return nil
+	},
+}
+
+var backupRestoreCmd = &cobra.Command{
+	Use:   "restore <archive>",
+	Short: "Restore a backup archive into the current profile",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if err := backup.Restore(f, backupFlags.force); err != nil {
+			return err
+		}
+		fmt.Printf("%sRestored %s\n", style.Emoji("📦 "), args[0])
+		return nil
+	},
+}
+
+func init() {
+	backupCreateCmd.Flags().StringVarP(&backupFlags.output, "output", "o", "goreilly-backup.tar.gz", "archive file to write")
+	backupCreateCmd.Flags().BoolVar(&backupFlags.includeSecrets, "include-secrets", false, "include token.json and cookies.json in the archive")
+	backupRestoreCmd.Flags().BoolVar(&backupFlags.force, "force", false, "replace existing files")
+	backupCmd.AddCommand(backupCreateCmd)
+	backupCmd.AddCommand(backupRestoreCmd)
+	rootCmd.AddCommand(backupCmd)
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tuannvm/koreilly/internal/cacheserver"
+	"github.com/tuannvm/koreilly/internal/catalog"
+)
+
+var cacheServeFlags struct {
+	addr  string
+	token string
+}
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Share downloaded books with teammates on the LAN",
+}
+
+var cacheServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve the local library as a team cache",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if cacheServeFlags.token == "" {
+			return fmt.Errorf("--token is required; peers must present it as a bearer token")
+		}
+		cat, err := catalog.Open(catalog.DefaultPath())
+		if err != nil {
+			return err
+		}
+		defer cat.Close()
+		srv := cacheserver.New(cat, cacheServeFlags.token)
+		fmt.Printf("Serving team cache on %s\n", cacheServeFlags.addr)
+		return http.ListenAndServe(cacheServeFlags.addr, srv)
+	},
+}
+
+func init() {
+	cacheServeCmd.Flags().StringVar(&cacheServeFlags.addr, "addr", ":8765", "listen address")
+	cacheServeCmd.Flags().StringVar(&cacheServeFlags.token, "token", "", "shared bearer token peers must present")
+	cacheCmd.AddCommand(cacheServeCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tuannvm/koreilly/internal/config"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect and edit goreilly settings",
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the current value of one setting",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		value, err := config.NewManager().Get(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Println(formatConfigValue(value))
+		return nil
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set one setting and write the config file",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := config.NewManager().SetKey(args[0], args[1]); err != nil {
+			return err
+		}
+		fmt.Printf("%s = %s\n", args[0], args[1])
+		return nil
+	},
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all settings with their current values",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		values, err := config.NewManager().All()
+		if err != nil {
+			return err
+		}
+		for _, key := range config.Keys() {
+			fmt.Printf("%-22s %s\n", key, formatConfigValue(values[key]))
+		}
+		return nil
+	},
+}
+
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Interactively create the config file",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager := config.NewManager()
+		if _, err := os.Stat(manager.Path()); err == nil {
+			return fmt.Errorf("%s already exists; edit it with goreilly config set", manager.Path())
+		}
+		cfg, err := manager.Load()
+		if err != nil {
+			return err
+		}
+		reader := bufio.NewReader(os.Stdin)
+		cfg.OutputDir = promptDefault(reader, "Download directory", cfg.OutputDir)
+		cfg.LogLevel = promptDefault(reader, "Log level (debug/info/warn/error)", cfg.LogLevel)
+		if err := manager.Save(cfg); err != nil {
+			return err
+		}
+		fmt.Printf("Wrote %s\n", manager.Path())
+		return nil
+	},
+}
+
+// promptDefault asks one wizard question, keeping the default on an
+// empty answer.
+func promptDefault(reader *bufio.Reader, label, def string) string {
+	fmt.Printf("%s [%s]: ", label, def)
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return def
+	}
+	if answer := strings.TrimSpace(line); answer != "" {
+		return answer
+	}
+	return def
+}
+
+func formatConfigValue(v any) string {
+	switch value := v.(type) {
+	case []string:
+		return strings.Join(value, ",")
+	case []any:
+		parts := make([]string, len(value))
+		for i, p := range value {
+			parts[i] = fmt.Sprint(p)
+		}
+		return strings.Join(parts, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func init() {
+	configCmd.AddCommand(configGetCmd, configSetCmd, configListCmd, configInitCmd)
+	rootCmd.AddCommand(configCmd)
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tuannvm/koreilly/internal/catalog"
+	"github.com/tuannvm/koreilly/internal/config"
+	"github.com/tuannvm/koreilly/internal/downloader"
+	"github.com/tuannvm/koreilly/internal/manifest"
+)
+
+var dedupeFlags struct {
+	hardlink bool
+}
+
+var libraryDedupeCmd = &cobra.Command{
+	Use:   "dedupe",
+	Short: "Find identical files in the library, optionally hard-linking them",
+	Long: `Dedupe groups library files by their SHA-256 and reports duplicates,
+as happens when overlapping learning paths are downloaded separately.
+With --hardlink, each duplicate is replaced by a hard link to the first
+copy, reclaiming the space without changing any paths.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		cat, err := catalog.Open(catalog.DefaultPath())
+		if err != nil {
+			return err
+		}
+		defer cat.Close()
+		records, err := cat.List()
+		if err != nil {
+			return err
+		}
+
+		byHash := make(map[string][]catalog.Record)
+		for _, r := range records {
+			sum := r.SHA256
+			// Older records predate hash recording; fill them in now.
+			if sum == "" {
+				if sum, err = manifest.FileSHA256(r.Path); err != nil {
+					continue // file missing; verify handles that
+				}
+				r.SHA256 = sum
+				_ = cat.Put(r)
+			}
+			byHash[sum] = append(byHash[sum], r)
+		}
+
+		var groups, reclaimed int
+		for _, group := range byHash {
+			if len(group) < 2 {
+				continue
+			}
+			groups++
+			fmt.Printf("%d identical copies:\n", len(group))
+			for _, r := range group {
+				fmt.Printf("  %s (%s)\n", r.Path, r.ID)
+			}
+			if !dedupeFlags.hardlink {
+				continue
+			}
+			keep := group[0].Path
+			for _, r := range group[1:] {
+				if r.Path == keep {
+					continue
+				}
+				if info, err := os.Stat(r.Path); err == nil {
+					reclaimed += int(info.Size())
+				}
+				if err := os.Remove(r.Path); err != nil {
+					return fmt.Errorf("replacing %s: %w", r.Path, err)
+				}
+				if err := os.Link(keep, r.Path); err != nil {
+					return fmt.Errorf("hard-linking %s: %w", r.Path, err)
+				}
+				downloader.AppendChangelog(cfg, fmt.Sprintf("removed duplicate %s (hard-linked to %s)", r.Path, keep))
+			}
+		}
+		switch {
+		case groups == 0:
+			fmt.Println("No duplicate files in the library.")
+		case dedupeFlags.hardlink:
+			fmt.Printf("%d duplicate groups hard-linked, %d bytes reclaimed\n", groups, reclaimed)
+		default:
+			fmt.Printf("%d duplicate groups found (re-run with --hardlink to reclaim space)\n", groups)
+		}
+		return nil
+	},
+}
+
+func init() {
+	libraryDedupeCmd.Flags().BoolVar(&dedupeFlags.hardlink, "hardlink", false, "replace duplicates with hard links to one copy")
+	libraryCmd.AddCommand(libraryDedupeCmd)
+}
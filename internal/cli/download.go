@@ -0,0 +1,373 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tuannvm/koreilly/internal/auth"
+	"github.com/tuannvm/koreilly/internal/catalog"
+	"github.com/tuannvm/koreilly/internal/client"
+	"github.com/tuannvm/koreilly/internal/config"
+	"github.com/tuannvm/koreilly/internal/convert"
+	"github.com/tuannvm/koreilly/internal/downloader"
+	"github.com/tuannvm/koreilly/internal/epub"
+	"github.com/tuannvm/koreilly/internal/manifest"
+	"github.com/tuannvm/koreilly/internal/netmeter"
+	"github.com/tuannvm/koreilly/internal/services/oreilly"
+	"github.com/tuannvm/koreilly/internal/stats"
+	"github.com/tuannvm/koreilly/internal/style"
+	"github.com/tuannvm/koreilly/internal/util"
+)
+
+var downloadFlags struct {
+	contentType  string
+	outputDir    string
+	output       string
+	allowMetered bool
+	formats      []string
+	convert      []string
+	chapters     string
+	limitRate    string
+	readme       bool
+	calibre      bool
+	print        bool
+	skipExisting bool
+	force        bool
+}
+
+// downloadPolicy maps the dedup flags onto the downloader's policy.
+func downloadPolicy() downloader.DedupPolicy {
+	switch {
+	case downloadFlags.force:
+		return downloader.DedupForce
+	case downloadFlags.skipExisting:
+		return downloader.DedupSkipExisting
+	default:
+		return downloader.DedupDefault
+	}
+}
+
+// stderrProgress reports download progress on stderr, keeping stdout
+// clean for piped output.
+func stderrProgress(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+}
+
+var downloadCmd = &cobra.Command{
+	Use:   "download <book-or-course-id>",
+	Short: "Download a book as EPUB or a video course",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := oreilly.ResolveID(args[0])
+		if err != nil {
+			return err
+		}
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		jwt, err := auth.NewService().Token()
+		if err != nil {
+			return err
+		}
+		outDir := cfg.OutputDir
+		if downloadFlags.outputDir != "" {
+			outDir = downloadFlags.outputDir
+		}
+		if !downloadFlags.allowMetered {
+			if status := netmeter.Detect(cfg.MeteredSSIDs); status.Metered {
+				return fmt.Errorf("download paused: %s (re-run with --allow-metered to override)", status.Reason)
+			}
+		}
+		if downloadFlags.limitRate != "" {
+			if _, err := client.ParseRate(downloadFlags.limitRate); err != nil {
+				return err
+			}
+			cfg.LimitRate = downloadFlags.limitRate
+		}
+		svc := newOreillyService(cfg)
+		switch downloadFlags.contentType {
+		case "book":
+			// A chapter selection always goes through direct assembly:
+			// the catalog and peer cache only deal in whole books.
+			var sel *downloader.ChapterSelection
+			if downloadFlags.chapters != "" {
+				sel, err = downloader.ParseChapterSpec(downloadFlags.chapters)
+				if err != nil {
+					return err
+				}
+			}
+			// "-o -" streams the EPUB to stdout for piping; the catalog
+			// and peer cache only deal in files on disk, so both are
+			// bypassed and all progress goes to stderr.
+			switch {
+			case downloadFlags.output == "-":
+				return downloadBookToStdout(cmd.Context(), svc, jwt, id, sel)
+			case downloadFlags.output != "":
+				return downloadBookToFile(cmd.Context(), svc, jwt, id, sel, downloadFlags.output)
+			case sel != nil:
+				return downloadBookChapters(cmd.Context(), svc, jwt, id, sel, outDir)
+			default:
+				return downloadBook(cmd, cfg, svc, jwt, id, outDir)
+			}
+		case "video":
+			return downloadVideo(cmd, svc, jwt, id, outDir)
+		default:
+			return fmt.Errorf("unknown --type %q (want book or video)", downloadFlags.contentType)
+		}
+	},
+}
+
+func downloadBook(cmd *cobra.Command, cfg *config.Config, svc *oreilly.Service, jwt, bookID, outDir string) error {
+	// NDJSON mode streams progress events on stdout; otherwise progress
+	// stays on stderr and stdout gets the human summary.
+	report := downloader.Progress(stderrProgress)
+	if outputFlag == "ndjson" {
+		report = ndjsonProgress
+	}
+	outPath, chapters, skipped, err := downloader.BookWith(cmd.Context(), cfg, svc, jwt, bookID, outDir, report, downloadPolicy())
+	if err != nil {
+		return err
+	}
+	switch {
+	case machineOutput():
+		event := map[string]any{"type": "done", "path": outPath, "chapters": chapters, "skipped": skipped}
+		if err := emitOne(event); err != nil {
+			return err
+		}
+	case skipped:
+		fmt.Printf("Skipped %s (already downloaded)\n", outPath)
+	default:
+		fmt.Printf("%sWrote %s (%d chapters)\n", style.Emoji("📘 "), outPath, chapters)
+	}
+
+	if downloadFlags.readme {
+		readmePath, err := downloader.WriteReadme(cmd.Context(), svc, jwt, bookID, outPath)
+		if err != nil {
+			return fmt.Errorf("writing README: %w", err)
+		}
+		if !machineOutput() {
+			fmt.Printf("Wrote %s\n", readmePath)
+		}
+	}
+
+	if downloadFlags.calibre {
+		paths, err := downloader.WriteCalibre(cmd.Context(), svc, jwt, bookID, outPath)
+		if err != nil {
+			return fmt.Errorf("writing Calibre metadata: %w", err)
+		}
+		if !machineOutput() {
+			for _, p := range paths {
+				fmt.Printf("Wrote %s\n", p)
+			}
+		}
+	}
+
+	// Extra formats are converted from the assembled EPUB so the
+	// content is only fetched once.
+	for _, format := range downloadFlags.formats {
+		if format == "epub" {
+			continue
+		}
+		// HTML is a static export of the fetched chapters rather than a
+		// Calibre conversion; repeat metadata requests hit the cache.
+		if format == "html" {
+			book, _, chapterList, err := downloader.FetchBook(cmd.Context(), svc, jwt, bookID, nil, stderrProgress)
+			if err != nil {
+				return err
+			}
+			dir, err := downloader.WriteHTML(book, chapterList, outDir)
+			if err != nil {
+				return err
+			}
+			if err := recordDownload(bookID, "html", dir); err != nil {
+				return err
+			}
+			fmt.Printf("Wrote %s\n", dir)
+			continue
+		}
+		var converted string
+		// --print produces a PDF suitable for paper: page numbers,
+		// running chapter headers, and soft-wrapped code listings.
+		if format == "pdf" && downloadFlags.print {
+			converted, err = convert.ToPDF(cmd.Context(), outPath, convert.PDFOptions{
+				PageNumbers:    true,
+				ChapterHeaders: true,
+				WrapCode:       true,
+			})
+		} else {
+			converted, err = convertFromEPUB(cmd.Context(), outPath, format)
+		}
+		if err != nil {
+			return fmt.Errorf("converting to %s: %w", format, err)
+		}
+		if err := recordDownload(bookID, format, converted); err != nil {
+			return err
+		}
+		fmt.Printf("Wrote %s\n", converted)
+	}
+
+	// Kindle targets go through the dedicated conversion pipeline, which
+	// can also fall back to kindlegen.
+	for _, target := range downloadFlags.convert {
+		converted, err := convert.FromEPUB(cmd.Context(), outPath, target)
+		if err != nil {
+			return err
+		}
+		if err := recordDownload(bookID, target, converted); err != nil {
+			return err
+		}
+		fmt.Printf("Wrote %s\n", converted)
+	}
+	return nil
+}
+
+// convertFromEPUB produces another format from an assembled EPUB by
+// shelling out to Calibre's ebook-convert.
+func convertFromEPUB(ctx context.Context, epubPath, format string) (string, error) {
+	converter, err := exec.LookPath("ebook-convert")
+	if err != nil {
+		return "", fmt.Errorf("%s output needs Calibre's ebook-convert on PATH", format)
+	}
+	outPath := strings.TrimSuffix(epubPath, ".epub") + "." + format
+	cmd := exec.CommandContext(ctx, converter, epubPath, outPath)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("ebook-convert failed: %v: %s", err, string(out))
+	}
+	return outPath, nil
+}
+
+// recordDownload stores a produced artifact in the catalog.
+func recordDownload(bookID, format, path string) error {
+	cat, err := catalog.Open(catalog.DefaultPath())
+	if err != nil {
+		return err
+	}
+	defer cat.Close()
+	sum, _ := manifest.FileSHA256(path)
+	return cat.Put(catalog.Record{ID: bookID, Title: bookID, Path: path, Format: format, SHA256: sum})
+}
+
+// downloadBookTo assembles a book into an EPUB under outDir and returns
+// the written path, chapter count, and whether an existing copy was
+// kept.
+func downloadBookTo(ctx context.Context, cfg *config.Config, svc *oreilly.Service, jwt, bookID, outDir string) (string, int, bool, error) {
+	return downloader.BookWith(ctx, cfg, svc, jwt, bookID, outDir, stderrProgress, downloader.DedupDefault)
+}
+
+// downloadBookChapters assembles just the selected chapters into an
+// EPUB under outDir, named after the book plus the selection. Partial
+// books are never recorded in the catalog.
+func downloadBookChapters(ctx context.Context, svc *oreilly.Service, jwt, bookID string, sel *downloader.ChapterSelection, outDir string) error {
+	book, meta, chapters, err := downloader.FetchBook(ctx, svc, jwt, bookID, sel, stderrProgress)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s (chapters %s)", book.Title, sel)
+	outPath := filepath.Join(outDir, util.SanitizeFilename(name)+".epub")
+	f, err := os.Create(outPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := epub.Build(f, meta, chapters); err != nil {
+		return err
+	}
+	fmt.Printf("%sWrote %s (%d chapters)\n", style.Emoji("📘 "), outPath, len(chapters))
+	return nil
+}
+
+// downloadBookToFile assembles the EPUB at an explicit path, skipping
+// the catalog and peer cache.
+func downloadBookToFile(ctx context.Context, svc *oreilly.Service, jwt, bookID string, sel *downloader.ChapterSelection, outPath string) error {
+	_, meta, chapters, err := downloader.FetchBook(ctx, svc, jwt, bookID, sel, stderrProgress)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(outPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := epub.Build(f, meta, chapters); err != nil {
+		return err
+	}
+	if sel == nil {
+		downloader.ClearSpool(bookID)
+	}
+	fmt.Printf("Wrote %s (%d chapters)\n", outPath, len(chapters))
+	return nil
+}
+
+// downloadBookToStdout streams the assembled EPUB to stdout for piping.
+func downloadBookToStdout(ctx context.Context, svc *oreilly.Service, jwt, bookID string, sel *downloader.ChapterSelection) error {
+	_, meta, chapters, err := downloader.FetchBook(ctx, svc, jwt, bookID, sel, stderrProgress)
+	if err != nil {
+		return err
+	}
+	if err := epub.Build(os.Stdout, meta, chapters); err != nil {
+		return err
+	}
+	if sel == nil {
+		downloader.ClearSpool(bookID)
+	}
+	fmt.Fprintf(os.Stderr, "Streamed %d chapters to stdout\n", len(chapters))
+	return nil
+}
+
+func downloadVideo(cmd *cobra.Command, svc *oreilly.Service, jwt, courseID, outDir string) error {
+	ctx := cmd.Context()
+	lessons, err := svc.ListVideoLessons(ctx, jwt, courseID)
+	if err != nil {
+		return err
+	}
+	if len(lessons) == 0 {
+		return fmt.Errorf("course %s has no lessons", courseID)
+	}
+	courseDir := filepath.Join(outDir, util.SanitizeFilename(courseID))
+	var total int64
+	for _, lesson := range lessons {
+		fmt.Printf("Downloading %02d-%02d %s...\n", lesson.SectionNum, lesson.LessonNum, lesson.Title)
+		path, err := svc.DownloadVideoLesson(ctx, jwt, lesson, courseDir)
+		if err != nil {
+			return err
+		}
+		if info, err := os.Stat(path); err == nil {
+			total += info.Size()
+		}
+	}
+	_ = stats.NewStore().Append(stats.Event{
+		Type:   stats.EventDownload,
+		BookID: courseID,
+		Bytes:  total,
+	})
+	fmt.Printf("Downloaded %d lessons to %s\n", len(lessons), courseDir)
+	return nil
+}
+
+func init() {
+	downloadCmd.Flags().StringVar(&downloadFlags.contentType, "type", "book", "content type: book or video")
+	downloadCmd.Flags().StringVarP(&downloadFlags.outputDir, "output-dir", "d", "", "override the configured output directory")
+	downloadCmd.Flags().StringVarP(&downloadFlags.output, "output-file", "o", "", `write the book to this destination; "-" streams to stdout`)
+	downloadCmd.Flags().BoolVar(&downloadFlags.allowMetered, "allow-metered", false, "download even on a metered connection")
+	downloadCmd.Flags().StringSliceVar(&downloadFlags.formats, "format", []string{"epub"}, "output formats, e.g. epub,pdf,mobi,html (content is fetched once)")
+	downloadCmd.Flags().StringSliceVar(&downloadFlags.convert, "convert", nil, "Kindle formats to convert the EPUB to, e.g. mobi,azw3")
+	downloadCmd.Flags().StringVar(&downloadFlags.chapters, "chapters", "", `download only these chapters, e.g. "1,4-6"`)
+	downloadCmd.Flags().StringVar(&downloadFlags.limitRate, "limit-rate", "", `cap download bandwidth, e.g. "500k" (or the limit_rate config key)`)
+	downloadCmd.Flags().BoolVar(&downloadFlags.readme, "readme", false, "write a Markdown summary of the book next to it")
+	downloadCmd.Flags().BoolVar(&downloadFlags.calibre, "calibre", false, "write a Calibre metadata.opf and cover next to the book")
+	downloadCmd.Flags().BoolVar(&downloadFlags.print, "print", false, "make --format pdf print-friendly: page numbers, chapter headers, wrapped code")
+	downloadCmd.Flags().BoolVar(&downloadFlags.skipExisting, "skip-existing", false, "skip when the destination file already exists, catalogued or not")
+	downloadCmd.Flags().BoolVar(&downloadFlags.force, "force", false, "re-download even when the catalog already has the book")
+	downloadCmd.MarkFlagsMutuallyExclusive("skip-existing", "force")
+	rootCmd.AddCommand(downloadCmd)
+}
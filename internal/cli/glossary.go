@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/spf13/cobra"
+
+	"github.com/tuannvm/koreilly/internal/config"
+	"github.com/tuannvm/koreilly/internal/sanitize"
+	"github.com/tuannvm/koreilly/internal/services/oreilly"
+)
+
+var glossaryFlags struct {
+	format string
+	output string
+}
+
+// glossaryEntry is one extracted term and its definition.
+type glossaryEntry struct {
+	Term       string `json:"term"`
+	Definition string `json:"definition"`
+}
+
+var glossaryCmd = &cobra.Command{
+	Use:   "glossary <book-id-or-url>",
+	Short: "Extract a book's glossary as Markdown or CSV",
+	Long: `Glossary pulls the definition lists out of a book's glossary chapter
+(or, when no chapter is titled that way, out of every chapter) and
+writes them as Markdown or CSV — handy raw material for study guides
+and flashcards.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := oreilly.ResolveID(args[0])
+		if err != nil {
+			return err
+		}
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		jwt, err := newAuthService().Token()
+		if err != nil {
+			return err
+		}
+		svc := newOreillyService(cfg)
+		toc, err := svc.FetchTOC(cmd.Context(), jwt, id)
+		if err != nil {
+			return err
+		}
+		flat := oreilly.FlattenChapters(toc)
+
+		candidates := flat[:0:0]
+		for _, ch := range flat {
+			if strings.Contains(strings.ToLower(ch.Title), "glossary") {
+				candidates = append(candidates, ch)
+			}
+		}
+		if len(candidates) == 0 {
+			fmt.Fprintln(os.Stderr, "No glossary chapter; scanning every chapter for definition lists...")
+			candidates = flat
+		}
+
+		var entries []glossaryEntry
+		seen := map[string]bool{}
+		for _, ch := range candidates {
+			if ch.Href == "" {
+				continue
+			}
+			content, err := svc.FetchChapterContent(cmd.Context(), jwt, id, ch.Href)
+			if err != nil {
+				return err
+			}
+			for _, e := range extractDefinitions(sanitize.Chapter(content)) {
+				if seen[e.Term] {
+					continue
+				}
+				seen[e.Term] = true
+				entries = append(entries, e)
+			}
+		}
+		if len(entries) == 0 {
+			return fmt.Errorf("no definition lists found in %s", id)
+		}
+
+		if machineOutput() {
+			return emitStructured(entries)
+		}
+		var out []byte
+		switch glossaryFlags.format {
+		case "md":
+			out = renderGlossaryMarkdown(entries)
+		case "csv":
+			out, err = renderGlossaryCSV(entries)
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unknown --format %q (want md or csv)", glossaryFlags.format)
+		}
+		if glossaryFlags.output != "" {
+			if err := os.WriteFile(glossaryFlags.output, out, 0o644); err != nil {
+				return err
+			}
+			fmt.Printf("Wrote %d terms to %s\n", len(entries), glossaryFlags.output)
+			return nil
+		}
+		fmt.Print(string(out))
+		return nil
+	},
+}
+
+// extractDefinitions pulls dt/dd pairs out of a chapter's definition
+// lists.
+func extractDefinitions(content []byte) []glossaryEntry {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(content))
+	if err != nil {
+		return nil
+	}
+	var entries []glossaryEntry
+	doc.Find("dl dt").Each(func(_ int, s *goquery.Selection) {
+		term := strings.TrimSpace(s.Text())
+		def := strings.TrimSpace(s.NextAllFiltered("dd").First().Text())
+		if term == "" || def == "" {
+			return
+		}
+		entries = append(entries, glossaryEntry{Term: term, Definition: def})
+	})
+	return entries
+}
+
+func renderGlossaryMarkdown(entries []glossaryEntry) []byte {
+	var b strings.Builder
+	b.WriteString("# Glossary\n\n")
+	for _, e := range entries {
+		fmt.Fprintf(&b, "**%s**\n: %s\n\n", e.Term, e.Definition)
+	}
+	return []byte(b.String())
+}
+
+func renderGlossaryCSV(entries []glossaryEntry) ([]byte, error) {
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	if err := w.Write([]string{"term", "definition"}); err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if err := w.Write([]string{e.Term, e.Definition}); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return b.Bytes(), w.Error()
+}
+
+func init() {
+	glossaryCmd.Flags().StringVar(&glossaryFlags.format, "format", "md", "output format: md or csv")
+	glossaryCmd.Flags().StringVarP(&glossaryFlags.output, "output-file", "o", "", "write to this file instead of stdout")
+	rootCmd.AddCommand(glossaryCmd)
+}
@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tuannvm/koreilly/internal/auth"
+	"github.com/tuannvm/koreilly/internal/config"
+	"github.com/tuannvm/koreilly/internal/services/oreilly"
+)
+
+var infoCmd = &cobra.Command{
+	Use:   "info <book-id-or-url>",
+	Short: "Show a book's metadata",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := oreilly.ResolveID(args[0])
+		if err != nil {
+			return err
+		}
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		jwt, err := auth.NewService().Token()
+		if err != nil {
+			return err
+		}
+		book, err := newOreillyService(cfg).FetchBookDetail(cmd.Context(), jwt, id)
+		if err != nil {
+			return err
+		}
+		if machineOutput() {
+			return emitOne(book)
+		}
+		fmt.Printf("Title:      %s\n", book.Title)
+		fmt.Printf("Authors:    %s\n", strings.Join(book.Authors, ", "))
+		fmt.Printf("ISBN:       %s\n", book.ISBN)
+		fmt.Printf("Publisher:  %s\n", strings.Join(book.Publishers, ", "))
+		fmt.Printf("Issued:     %s\n", book.Issued)
+		if book.Level != "" {
+			fmt.Printf("Level:      %s\n", book.Level)
+		}
+		if book.AverageRating > 0 {
+			fmt.Printf("Rating:     %.1f/5\n", book.AverageRating)
+		}
+		if book.PopularityRank > 0 {
+			fmt.Printf("Popularity: #%d\n", book.PopularityRank)
+		}
+		if book.Description != "" {
+			fmt.Printf("\n%s\n", book.Description)
+		}
+		return nil
+	},
+}
+
+var tocFlags struct {
+	times bool
+}
+
+var tocCmd = &cobra.Command{
+	Use:   "toc <book-id-or-url>",
+	Short: "Show a book's table of contents",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := oreilly.ResolveID(args[0])
+		if err != nil {
+			return err
+		}
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		jwt, err := auth.NewService().Token()
+		if err != nil {
+			return err
+		}
+		svc := newOreillyService(cfg)
+		toc, err := svc.FetchTOC(cmd.Context(), jwt, id)
+		if err != nil {
+			return err
+		}
+		if tocFlags.times {
+			// Each chapter file is fetched once even when several TOC
+			// entries point into it via fragments.
+			words := map[string]int{}
+			for _, ch := range oreilly.FlattenChapters(toc) {
+				if ch.Href == "" {
+					continue
+				}
+				if _, ok := words[ch.Href]; ok {
+					continue
+				}
+				content, err := svc.FetchChapterContent(cmd.Context(), jwt, id, ch.Href)
+				if err != nil {
+					return fmt.Errorf("fetching %s for word count: %w", ch.Href, err)
+				}
+				words[ch.Href] = oreilly.WordCount(content)
+			}
+			annotateWordCounts(toc, words)
+		}
+		// json emits the chapter tree; ndjson one flattened entry per
+		// line.
+		switch outputFlag {
+		case "json":
+			return emitOne(toc)
+		case "ndjson":
+			return emitStructured(oreilly.FlattenChapters(toc))
+		}
+		for _, ch := range oreilly.FlattenChapters(toc) {
+			fmt.Printf("%s%s", strings.Repeat("  ", ch.Depth), ch.Title)
+			if ch.Words > 0 {
+				fmt.Printf("  (~%d min)", oreilly.ReadingMinutes(ch.Words))
+			}
+			fmt.Println()
+		}
+		return nil
+	},
+}
+
+// annotateWordCounts fills in Words across the chapter tree from a map
+// of per-file counts.
+func annotateWordCounts(chapters []oreilly.Chapter, words map[string]int) {
+	for i := range chapters {
+		chapters[i].Words = words[chapters[i].Href]
+		annotateWordCounts(chapters[i].Children, words)
+	}
+}
+
+func init() {
+	tocCmd.Flags().BoolVar(&tocFlags.times, "times", false, "fetch chapter contents and show estimated reading times")
+	rootCmd.AddCommand(infoCmd, tocCmd)
+}
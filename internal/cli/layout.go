@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tuannvm/koreilly/internal/catalog"
+	"github.com/tuannvm/koreilly/internal/config"
+	"github.com/tuannvm/koreilly/internal/util"
+)
+
+var layoutFlags struct {
+	by  string
+	dir string
+}
+
+var libraryLayoutCmd = &cobra.Command{
+	Use:   "layout",
+	Short: "Build a browsable symlink hierarchy over the flat library",
+	Long: `Layout creates a directory of symlinks organizing the library by
+topic, author, or download year, so the same flat set of files can be
+browsed several ways without duplicating anything.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch layoutFlags.by {
+		case "topic", "author", "year":
+		default:
+			return fmt.Errorf("unknown --by %q (want topic, author, or year)", layoutFlags.by)
+		}
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		root := layoutFlags.dir
+		if root == "" {
+			root = filepath.Join(cfg.OutputDir, "by-"+layoutFlags.by)
+		}
+
+		cat, err := catalog.Open(catalog.DefaultPath())
+		if err != nil {
+			return err
+		}
+		defer cat.Close()
+		records, err := cat.List()
+		if err != nil {
+			return err
+		}
+
+		var links int
+		for _, r := range records {
+			target, err := filepath.Abs(r.Path)
+			if err != nil {
+				continue
+			}
+			if _, err := os.Stat(target); err != nil {
+				continue
+			}
+			for _, key := range layoutKeys(r) {
+				dir := filepath.Join(root, util.SanitizeFilename(key))
+				if err := os.MkdirAll(dir, 0o755); err != nil {
+					return err
+				}
+				link := filepath.Join(dir, filepath.Base(r.Path))
+				if _, err := os.Lstat(link); err == nil {
+					continue // already laid out on a previous run
+				}
+				if err := os.Symlink(target, link); err != nil {
+					return fmt.Errorf("linking %s: %w", link, err)
+				}
+				links++
+			}
+		}
+		fmt.Printf("%d links under %s\n", links, root)
+		return nil
+	},
+}
+
+// layoutKeys returns the bucket names a record belongs to for the
+// selected grouping; a record can land in several (one per author or
+// topic).
+func layoutKeys(r catalog.Record) []string {
+	switch layoutFlags.by {
+	case "author":
+		if len(r.Authors) == 0 {
+			return []string{"unknown-author"}
+		}
+		return r.Authors
+	case "topic":
+		if len(r.Topics) == 0 {
+			return []string{"unsorted"}
+		}
+		return r.Topics
+	case "year":
+		return []string{r.DownloadedAt.Format("2006")}
+	}
+	return nil
+}
+
+func init() {
+	libraryLayoutCmd.Flags().StringVar(&layoutFlags.by, "by", "topic", "grouping: topic, author, or year")
+	libraryLayoutCmd.Flags().StringVar(&layoutFlags.dir, "dir", "", "where to build the hierarchy (default <output_dir>/by-<grouping>)")
+	libraryCmd.AddCommand(libraryLayoutCmd)
+}
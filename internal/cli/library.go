@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tuannvm/koreilly/internal/catalog"
+	"github.com/tuannvm/koreilly/internal/config"
+	"github.com/tuannvm/koreilly/internal/progress"
+	"github.com/tuannvm/koreilly/internal/util"
+)
+
+var libraryFlags struct {
+	local    bool
+	progress bool
+	table    tableFlags
+}
+
+var libraryCmd = &cobra.Command{
+	Use:   "library",
+	Short: "List books in the local library",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		c, err := catalog.Open(catalog.DefaultPath())
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+		records, err := c.List()
+		if err != nil {
+			return err
+		}
+		// Platform progress is opt-in: it needs a network round trip,
+		// and the library listing otherwise works offline.
+		remote := map[string]float64{}
+		if libraryFlags.progress {
+			jwt, err := newAuthService().Token()
+			if err != nil {
+				return err
+			}
+			remote, err = newOreillyService(cfg).FetchProgress(cmd.Context(), jwt)
+			if err != nil {
+				return err
+			}
+			libraryFlags.table.columns = append(libraryFlags.table.columns, "progress")
+		}
+		if machineOutput() {
+			return emitStructured(records)
+		}
+		if len(records) == 0 {
+			fmt.Println("Library is empty — download something first.")
+			return nil
+		}
+		err = renderTable(libraryFlags.table, records, map[string]func(catalog.Record) string{
+			"progress": func(r catalog.Record) string {
+				if pct, ok := remote[r.ID]; ok {
+					return fmt.Sprintf("%.0f%%", pct*100)
+				}
+				return "-"
+			},
+			"id":     func(r catalog.Record) string { return r.ID },
+			"format": func(r catalog.Record) string { return r.Format },
+			"date":   func(r catalog.Record) string { return util.FormatDate(r.DownloadedAt, cfg.Format.Dates) },
+			"title":  func(r catalog.Record) string { return r.Title },
+			"author": func(r catalog.Record) string { return strings.Join(r.Authors, ", ") },
+			"path":   func(r catalog.Record) string { return r.Path },
+			"isbn":   func(r catalog.Record) string { return r.ISBN },
+			"sha256": func(r catalog.Record) string { return r.SHA256 },
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d books\n", len(records))
+		return nil
+	},
+}
+
+var libraryPushProgressCmd = &cobra.Command{
+	Use:   "push-progress",
+	Short: "Report local reading positions back to the platform",
+	Long: `Push-progress uploads the positions the terminal reader recorded, so
+the website and apps resume where local reading stopped.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		positions, err := progress.NewStore().All()
+		if err != nil {
+			return err
+		}
+		if len(positions) == 0 {
+			fmt.Println("No local reading positions to push.")
+			return nil
+		}
+		jwt, err := newAuthService().Token()
+		if err != nil {
+			return err
+		}
+		svc := newOreillyService(cfg)
+		var pushed int
+		for _, pos := range positions {
+			if pos.Percent <= 0 {
+				continue
+			}
+			if err := svc.PostProgress(cmd.Context(), jwt, pos.BookID, pos.Percent); err != nil {
+				return fmt.Errorf("%s: %w", pos.BookTitle, err)
+			}
+			pushed++
+		}
+		fmt.Printf("Pushed progress for %d books\n", pushed)
+		return nil
+	},
+}
+
+func init() {
+	libraryCmd.Flags().BoolVar(&libraryFlags.local, "local", true, "list local holdings from the catalog")
+	libraryCmd.Flags().BoolVar(&libraryFlags.progress, "progress", false, "fetch platform reading progress and add a progress column")
+	libraryFlags.table.addTableFlags(libraryCmd, []string{"id", "format", "date", "title", "author"})
+	libraryCmd.AddCommand(libraryPushProgressCmd)
+	rootCmd.AddCommand(libraryCmd)
+}
@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/tuannvm/koreilly/internal/auth"
+	"github.com/tuannvm/koreilly/internal/style"
+)
+
+var loginFlags struct {
+	email       string
+	password    string
+	strategy    string
+	jwt         string
+	cookiesFile string
+	browser     bool
+}
+
+var loginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Log in to O'Reilly and store the session token",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		svc := auth.NewService()
+		strategy, err := loginStrategy(svc)
+		if err != nil {
+			return err
+		}
+		tok, err := svc.Login(cmd.Context(), strategy)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%sLogged in. Session valid until %s.\n", style.Emoji("🔓 "), tok.ExpiresAt.Format("2006-01-02 15:04:05"))
+		return nil
+	},
+}
+
+// loginStrategy builds the auth strategy selected by --strategy,
+// prompting for anything still missing.
+func loginStrategy(svc *auth.Service) (auth.Strategy, error) {
+	if loginFlags.browser {
+		loginFlags.strategy = "browser"
+	}
+	switch loginFlags.strategy {
+	case "password":
+		email := loginFlags.email
+		if email == "" {
+			email = os.Getenv("GOREILLY_EMAIL")
+		}
+		if email == "" {
+			fmt.Print("Email: ")
+			line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+			if err != nil {
+				return nil, err
+			}
+			email = strings.TrimSpace(line)
+		}
+		if email == "" {
+			return nil, fmt.Errorf("an email address is required")
+		}
+
+		password := loginFlags.password
+		if password == "" {
+			password = os.Getenv("GOREILLY_PASSWORD")
+		}
+		if password == "" {
+			fmt.Print("Password: ")
+			raw, err := term.ReadPassword(int(syscall.Stdin))
+			fmt.Println()
+			if err != nil {
+				return nil, fmt.Errorf("reading password: %w", err)
+			}
+			password = string(raw)
+		}
+		return &auth.PasswordLogin{Service: svc, Email: email, Password: password, OTPPrompt: func() (string, error) {
+			fmt.Print("One-time code: ")
+			line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+			if err != nil {
+				return "", fmt.Errorf("reading one-time code: %w", err)
+			}
+			return strings.TrimSpace(line), nil
+		}}, nil
+	case "jwt":
+		jwt := loginFlags.jwt
+		if jwt == "" {
+			jwt = os.Getenv("GOREILLY_JWT")
+		}
+		if jwt == "" {
+			fmt.Print("Paste orm-jwt: ")
+			raw, err := term.ReadPassword(int(syscall.Stdin))
+			fmt.Println()
+			if err != nil {
+				return nil, fmt.Errorf("reading token: %w", err)
+			}
+			jwt = strings.TrimSpace(string(raw))
+		}
+		return &auth.StaticJWT{JWT: jwt}, nil
+	case "cookies":
+		if loginFlags.cookiesFile == "" {
+			return nil, fmt.Errorf("--strategy cookies needs --cookies-file")
+		}
+		return &auth.CookieImport{Path: loginFlags.cookiesFile}, nil
+	case "safari":
+		// --cookies-file overrides the store location; empty probes the
+		// standard Safari paths.
+		return &auth.SafariCookies{Path: loginFlags.cookiesFile}, nil
+	case "browser":
+		return &auth.BrowserHandoff{Prompt: func(url string) {
+			fmt.Printf("Visit %s to hand your browser session to goreilly.\n", url)
+		}}, nil
+	default:
+		return nil, fmt.Errorf("unknown --strategy %q (want password, jwt, cookies, safari, or browser)", loginFlags.strategy)
+	}
+}
+
+var logoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Clear the stored session token",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := auth.NewService().Logout(); err != nil {
+			return err
+		}
+		fmt.Println("Logged out.")
+		return nil
+	},
+}
+
+func init() {
+	loginCmd.Flags().StringVar(&loginFlags.email, "email", "", "account email (or GOREILLY_EMAIL)")
+	loginCmd.Flags().StringVar(&loginFlags.password, "password", "", "account password (or GOREILLY_PASSWORD; prompted when unset)")
+	loginCmd.Flags().StringVar(&loginFlags.strategy, "strategy", "password", "how to log in: password, jwt, cookies, safari, or browser")
+	loginCmd.Flags().BoolVar(&loginFlags.browser, "browser", false, "shorthand for --strategy browser")
+	loginCmd.Flags().StringVar(&loginFlags.jwt, "jwt", "", "session token for --strategy jwt (or GOREILLY_JWT)")
+	loginCmd.Flags().StringVar(&loginFlags.cookiesFile, "cookies-file", "", "JSON cookie export for --strategy cookies (or the binarycookies path for --strategy safari)")
+	rootCmd.AddCommand(loginCmd, logoutCmd)
+}
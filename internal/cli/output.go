@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// outputFlag holds the global --output value: table (the default
+// human-readable rendering), json, or ndjson.
+var outputFlag string
+
+// validateOutput rejects unknown --output values early, before any
+// command runs.
+func validateOutput() error {
+	switch outputFlag {
+	case "table", "json", "ndjson":
+		return nil
+	default:
+		return fmt.Errorf("unknown --output %q (want table, json, or ndjson)", outputFlag)
+	}
+}
+
+// machineOutput reports whether stdout should carry structured data
+// instead of human-readable text.
+func machineOutput() bool {
+	return outputFlag == "json" || outputFlag == "ndjson"
+}
+
+// emitStructured writes rows in the selected machine format: one
+// indented JSON array for json, one object per line for ndjson.
+func emitStructured[T any](rows []T) error {
+	switch outputFlag {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	case "ndjson":
+		enc := json.NewEncoder(os.Stdout)
+		for _, row := range rows {
+			if err := enc.Encode(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("emitStructured called with --output %q", outputFlag)
+	}
+}
+
+// emitOne writes a single document, regardless of json vs ndjson.
+func emitOne(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	if outputFlag == "json" {
+		enc.SetIndent("", "  ")
+	}
+	return enc.Encode(v)
+}
+
+// ndjsonProgress reports download progress as NDJSON events on stdout,
+// for scripts that follow along. It matches the downloader's Progress
+// signature.
+func ndjsonProgress(format string, args ...any) {
+	_ = emitOne(map[string]string{
+		"type":    "progress",
+		"message": fmt.Sprintf(format, args...),
+	})
+}
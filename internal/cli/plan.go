@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tuannvm/koreilly/internal/auth"
+	"github.com/tuannvm/koreilly/internal/config"
+	"github.com/tuannvm/koreilly/internal/plan"
+	"github.com/tuannvm/koreilly/internal/services/oreilly"
+)
+
+var planFlags struct {
+	start   string
+	session time.Duration
+	perDay  int
+}
+
+var planCmd = &cobra.Command{
+	Use:   "plan <book-id>",
+	Short: "Build a chapter-by-chapter reading plan for a book",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jwt, err := auth.NewService().Token()
+		if err != nil {
+			return err
+		}
+		start := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+		if planFlags.start != "" {
+			start, err = time.ParseInLocation("2006-01-02 15:04", planFlags.start, time.Local)
+			if err != nil {
+				return fmt.Errorf("--start must be \"YYYY-MM-DD HH:MM\": %w", err)
+			}
+		}
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		svc := newOreillyService(cfg)
+		chapters, err := svc.FetchTOC(cmd.Context(), jwt, args[0])
+		if err != nil {
+			return err
+		}
+		p := plan.Build(args[0], args[0], oreilly.FlattenChapters(chapters), start, planFlags.session, planFlags.perDay)
+		if err := p.Save(); err != nil {
+			return err
+		}
+		fmt.Printf("Planned %d reading sessions starting %s\n", len(p.Sessions), start.Format("2006-01-02 15:04"))
+		return nil
+	},
+}
+
+var planICSFlags struct {
+	output string
+}
+
+var planICSCmd = &cobra.Command{
+	Use:   "ics",
+	Short: "Export the saved reading plan as an iCalendar file",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p, err := plan.Load()
+		if err != nil {
+			return err
+		}
+		out := os.Stdout
+		if planICSFlags.output != "" {
+			f, err := os.Create(planICSFlags.output)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			out = f
+		}
+		if err := p.WriteICS(out); err != nil {
+			return err
+		}
+		if planICSFlags.output != "" {
+			fmt.Printf("Wrote %d events to %s\n", len(p.Sessions), planICSFlags.output)
+		}
+		return nil
+	},
+}
+
+func init() {
+	planCmd.Flags().StringVar(&planFlags.start, "start", "", "first session start (\"YYYY-MM-DD HH:MM\", default tomorrow)")
+	planCmd.Flags().DurationVar(&planFlags.session, "session", 45*time.Minute, "length of each reading session")
+	planCmd.Flags().IntVar(&planFlags.perDay, "per-day", 1, "reading sessions per day")
+	planICSCmd.Flags().StringVarP(&planICSFlags.output, "output", "o", "", "write ICS to file instead of stdout")
+	planCmd.AddCommand(planICSCmd)
+	rootCmd.AddCommand(planCmd)
+}
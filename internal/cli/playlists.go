@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tuannvm/koreilly/internal/config"
+	"github.com/tuannvm/koreilly/internal/downloader"
+	"github.com/tuannvm/koreilly/internal/services/oreilly"
+	"github.com/tuannvm/koreilly/internal/util"
+)
+
+var playlistsFlags struct {
+	dir         string
+	concurrency int
+	table       tableFlags
+}
+
+var playlistsCmd = &cobra.Command{
+	Use:   "playlists",
+	Short: "List and download O'Reilly playlists",
+}
+
+var playlistsLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List your own and followed playlists",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		jwt, err := newAuthService().Token()
+		if err != nil {
+			return err
+		}
+		playlists, err := newOreillyService(cfg).ListPlaylists(cmd.Context(), jwt)
+		if err != nil {
+			return err
+		}
+		if machineOutput() {
+			return emitStructured(playlists)
+		}
+		if len(playlists) == 0 {
+			fmt.Println("No playlists.")
+			return nil
+		}
+		return renderTable(playlistsFlags.table, playlists, map[string]func(oreilly.Playlist) string{
+			"id":   func(p oreilly.Playlist) string { return p.ID },
+			"name": func(p oreilly.Playlist) string { return p.Name },
+			"relation": func(p oreilly.Playlist) string {
+				if p.IsOwned {
+					return "owned"
+				}
+				return "followed"
+			},
+			"description": func(p oreilly.Playlist) string { return p.Description },
+		})
+	},
+}
+
+var playlistsDownloadCmd = &cobra.Command{
+	Use:   "download <playlist-id>",
+	Short: "Download every book in a playlist into its own folder",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		jwt, err := newAuthService().Token()
+		if err != nil {
+			return err
+		}
+		svc := newOreillyService(cfg)
+		playlist, err := svc.FetchPlaylist(cmd.Context(), jwt, args[0])
+		if err != nil {
+			return err
+		}
+
+		// Each playlist lands in a folder named after it, so two
+		// playlists sharing a title never mix files.
+		root := playlistsFlags.dir
+		if root == "" {
+			root = cfg.OutputDir
+		}
+		outDir := filepath.Join(root, util.SanitizeFilename(playlist.Name))
+
+		q := downloader.NewQueue()
+		var queued, skipped int
+		for _, item := range playlist.Content {
+			// Videos and courses go through their own commands; a batch
+			// playlist run sticks to books.
+			if item.Format != "" && item.Format != "book" {
+				skipped++
+				continue
+			}
+			if err := q.Add(item.ProductID, item.Title); err != nil {
+				return err
+			}
+			queued++
+		}
+		if skipped > 0 {
+			fmt.Printf("Skipping %d non-book items\n", skipped)
+		}
+		if queued == 0 {
+			fmt.Println("Nothing to download.")
+			return nil
+		}
+		fmt.Printf("Downloading %d books from %q into %s\n", queued, playlist.Name, outDir)
+		if err := q.Process(cmd.Context(), cfg, svc, jwt, outDir, playlistsFlags.concurrency, stderrProgress); err != nil {
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	playlistsDownloadCmd.Flags().StringVar(&playlistsFlags.dir, "dir", "", "parent directory for the playlist folder (defaults to the output dir)")
+	playlistsDownloadCmd.Flags().IntVar(&playlistsFlags.concurrency, "concurrency", 2, "how many downloads to run at once")
+	playlistsFlags.table.addTableFlags(playlistsLsCmd, []string{"id", "name", "relation"})
+	playlistsCmd.AddCommand(playlistsLsCmd, playlistsDownloadCmd)
+	rootCmd.AddCommand(playlistsCmd)
+}
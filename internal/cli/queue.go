@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tuannvm/koreilly/internal/auth"
+	"github.com/tuannvm/koreilly/internal/catalog"
+	"github.com/tuannvm/koreilly/internal/config"
+	"github.com/tuannvm/koreilly/internal/downloader"
+	"github.com/tuannvm/koreilly/internal/services/oreilly"
+	"github.com/tuannvm/koreilly/internal/util"
+)
+
+var queueFlags struct {
+	concurrency int
+	table       tableFlags
+}
+
+var queueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "Manage the persistent download queue",
+}
+
+var queueAddCmd = &cobra.Command{
+	Use:   "add <book-id-or-url>...",
+	Short: "Enqueue books for a later (or concurrent) download run",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		q := downloader.NewQueue()
+		for _, arg := range args {
+			id, err := oreilly.ResolveID(arg)
+			if err != nil {
+				return err
+			}
+			if err := q.Add(id, ""); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("Queued %d books\n", len(args))
+		return nil
+	},
+}
+
+var queueLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List queue items and their status",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		items, err := downloader.NewQueue().List()
+		if err != nil {
+			return err
+		}
+		if machineOutput() {
+			return emitStructured(items)
+		}
+		if len(items) == 0 {
+			fmt.Println("Queue is empty.")
+			return nil
+		}
+		return renderTable(queueFlags.table, items, map[string]func(catalog.QueueItem) string{
+			"id":     func(i catalog.QueueItem) string { return i.ID },
+			"status": func(i catalog.QueueItem) string { return i.Status },
+			"title":  func(i catalog.QueueItem) string { return i.Title },
+			"queued": func(i catalog.QueueItem) string { return util.FormatDate(i.EnqueuedAt, "iso") },
+			"error":  func(i catalog.QueueItem) string { return i.Error },
+		})
+	},
+}
+
+var queueRmCmd = &cobra.Command{
+	Use:   "rm <book-id>...",
+	Short: "Remove items from the queue",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		q := downloader.NewQueue()
+		for _, id := range args {
+			if err := q.Remove(id); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+var queuePauseCmd = &cobra.Command{
+	Use:   "pause <book-id>",
+	Short: "Pause a queued item",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return downloader.NewQueue().Pause(args[0])
+	},
+}
+
+var queueResumeCmd = &cobra.Command{
+	Use:   "resume <book-id>",
+	Short: "Put a paused or failed item back in line",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return downloader.NewQueue().Resume(args[0])
+	},
+}
+
+var queueRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Download everything queued, a few books at a time",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		jwt, err := auth.NewService().Token()
+		if err != nil {
+			return err
+		}
+		svc := newOreillyService(cfg)
+		q := downloader.NewQueue()
+		if err := q.Process(cmd.Context(), cfg, svc, jwt, cfg.OutputDir, queueFlags.concurrency, stderrProgress); err != nil {
+			return err
+		}
+		items, err := q.List()
+		if err != nil {
+			return err
+		}
+		var done, failed, waiting int
+		for _, item := range items {
+			switch item.Status {
+			case catalog.QueueDone:
+				done++
+			case catalog.QueueFailed:
+				failed++
+			case catalog.QueueQueued, catalog.QueuePaused:
+				waiting++
+			}
+		}
+		fmt.Printf("Queue run finished: %d done, %d failed, %d still waiting\n", done, failed, waiting)
+		return nil
+	},
+}
+
+func init() {
+	queueRunCmd.Flags().IntVar(&queueFlags.concurrency, "concurrency", 2, "how many downloads to run at once")
+	queueFlags.table.addTableFlags(queueLsCmd, []string{"id", "status", "title", "error"})
+	queueCmd.AddCommand(queueAddCmd, queueLsCmd, queueRmCmd, queuePauseCmd, queueResumeCmd, queueRunCmd)
+	rootCmd.AddCommand(queueCmd)
+}
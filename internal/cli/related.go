@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tuannvm/koreilly/internal/config"
+	"github.com/tuannvm/koreilly/internal/downloader"
+	"github.com/tuannvm/koreilly/internal/services/oreilly"
+)
+
+var relatedFlags struct {
+	queue bool
+	table tableFlags
+}
+
+var relatedCmd = &cobra.Command{
+	Use:   "related <book-id-or-url>",
+	Short: "List titles readers of a book also viewed",
+	Long: `Related asks the platform for the titles it recommends alongside a
+known good one, so discovery can start from a book instead of a search
+query. With --queue every hit is added to the download queue directly.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := oreilly.ResolveID(args[0])
+		if err != nil {
+			return err
+		}
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		jwt, err := newAuthService().Token()
+		if err != nil {
+			return err
+		}
+		books, err := newOreillyService(cfg).FetchRelated(cmd.Context(), jwt, id)
+		if err != nil {
+			return err
+		}
+		if len(books) == 0 {
+			fmt.Println("No related titles found.")
+			return nil
+		}
+		if relatedFlags.queue {
+			q := downloader.NewQueue()
+			for _, b := range books {
+				if err := q.Add(b.ProductID, b.Title); err != nil {
+					return err
+				}
+			}
+			fmt.Printf("Queued %d related titles\n", len(books))
+			return nil
+		}
+		if machineOutput() {
+			return emitStructured(books)
+		}
+		return renderTable(relatedFlags.table, books, map[string]func(oreilly.Book) string{
+			"id":        func(b oreilly.Book) string { return b.ProductID },
+			"format":    func(b oreilly.Book) string { return b.Format },
+			"title":     func(b oreilly.Book) string { return b.Title },
+			"author":    func(b oreilly.Book) string { return strings.Join(b.Authors, ", ") },
+			"publisher": func(b oreilly.Book) string { return strings.Join(b.Publishers, ", ") },
+			"date":      func(b oreilly.Book) string { return b.Issued },
+		})
+	},
+}
+
+func init() {
+	relatedCmd.Flags().BoolVar(&relatedFlags.queue, "queue", false, "enqueue every related title for download")
+	relatedFlags.table.addTableFlags(relatedCmd, []string{"id", "format", "title", "author"})
+	rootCmd.AddCommand(relatedCmd)
+}
@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"archive/zip"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tuannvm/koreilly/internal/config"
+	"github.com/tuannvm/koreilly/internal/logging"
+)
+
+// currentRunID is generated once per invocation and stamped on every
+// log line, so a report can point at the exact failing run.
+var currentRunID string
+
+func runID() string {
+	if currentRunID == "" {
+		var b [4]byte
+		_, _ = rand.Read(b[:])
+		currentRunID = hex.EncodeToString(b[:])
+	}
+	return currentRunID
+}
+
+// maxReportLog caps how much of the log file ends up in the bundle —
+// the most recent half megabyte covers plenty of failing runs.
+const maxReportLog = 512 << 10
+
+// secretLine matches config lines whose key smells like a credential;
+// the value is replaced before the file enters the bundle.
+var secretLine = regexp.MustCompile(`(?im)^(\s*[\w-]*(?:password|token|secret|jwt)[\w-]*\s*:\s*).+$`)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Bundle logs, sanitized config, and version info for a bug report",
+	Long: `Report writes a zip file containing the recent log, the configuration
+with credentials redacted, and version details, ready to attach to a
+GitHub issue. Nothing leaves the machine until you attach it yourself.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := fmt.Sprintf("goreilly-report-%s.zip", time.Now().Format("20060102-150405"))
+		f, err := os.Create(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		zw := zip.NewWriter(f)
+
+		add := func(name string, data []byte) error {
+			w, err := zw.Create(name)
+			if err != nil {
+				return err
+			}
+			_, err = w.Write(data)
+			return err
+		}
+
+		if err := add("version.txt", []byte(versionInfo())); err != nil {
+			return err
+		}
+		if raw, err := os.ReadFile(config.Path()); err == nil {
+			if err := add("config.yaml", secretLine.ReplaceAll(raw, []byte("${1}[redacted]"))); err != nil {
+				return err
+			}
+		}
+		if raw, err := os.ReadFile(logging.FilePath()); err == nil {
+			if len(raw) > maxReportLog {
+				raw = raw[len(raw)-maxReportLog:]
+			}
+			if err := add("goreilly.log", raw); err != nil {
+				return err
+			}
+		}
+		if err := add("environment.txt", []byte(environmentInfo())); err != nil {
+			return err
+		}
+		if err := add("README.txt", []byte(reportReadme())); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		fmt.Printf("Wrote %s — attach it to a GitHub issue.\n", name)
+		fmt.Println("Check the contents first if you want; credentials are redacted automatically.")
+		return nil
+	},
+}
+
+// versionInfo collects what `go version -m` would say about this
+// binary, plus the platform.
+func versionInfo() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "go: %s\nplatform: %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	if info, ok := debug.ReadBuildInfo(); ok {
+		fmt.Fprintf(&b, "module: %s %s\n", info.Main.Path, info.Main.Version)
+		for _, s := range info.Settings {
+			if s.Key == "vcs.revision" || s.Key == "vcs.time" || s.Key == "vcs.modified" {
+				fmt.Fprintf(&b, "%s: %s\n", s.Key, s.Value)
+			}
+		}
+	}
+	if p := config.Profile(); p != "" {
+		fmt.Fprintf(&b, "profile: %s\n", p)
+	}
+	return b.String()
+}
+
+// environmentInfo lists the GOREILLY_* variables in effect, with
+// credential values redacted.
+func environmentInfo() string {
+	var b strings.Builder
+	for _, kv := range os.Environ() {
+		if !strings.HasPrefix(kv, "GOREILLY_") {
+			continue
+		}
+		key, value, _ := strings.Cut(kv, "=")
+		upper := strings.ToUpper(key)
+		if strings.Contains(upper, "JWT") || strings.Contains(upper, "TOKEN") || strings.Contains(upper, "PASSWORD") {
+			value = logging.Redact(value)
+		}
+		fmt.Fprintf(&b, "%s=%s\n", key, value)
+	}
+	if b.Len() == 0 {
+		return "no GOREILLY_* variables set\n"
+	}
+	return b.String()
+}
+
+func reportReadme() string {
+	return `This bundle was generated by "goreilly report".
+
+Contents:
+  version.txt      build and platform details
+  config.yaml      configuration with credentials redacted
+  goreilly.log     the most recent log output (if logging to file worked)
+  environment.txt  GOREILLY_* environment variables, credentials redacted
+
+Every log line carries a run=<id> attribute identifying one invocation.
+When filing an issue, mention the run id of the command that failed so
+its lines can be picked out of the log.
+`
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+}
@@ -0,0 +1,159 @@
+// Package cli wires up the goreilly command tree.
+package cli
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tuannvm/koreilly/internal/auth"
+	"github.com/tuannvm/koreilly/internal/client"
+	"github.com/tuannvm/koreilly/internal/config"
+	"github.com/tuannvm/koreilly/internal/logging"
+	"github.com/tuannvm/koreilly/internal/migrate"
+	"github.com/tuannvm/koreilly/internal/services/oreilly"
+	"github.com/tuannvm/koreilly/internal/style"
+	"github.com/tuannvm/koreilly/internal/tui"
+)
+
+var (
+	profileFlag    string
+	noColorFlag    bool
+	noEmojiFlag    bool
+	insecureFlag   bool
+	jwtFlag        string
+	rpsFlag        float64
+	burstFlag      int
+	maxRetriesFlag int
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "goreilly",
+	Short: "Download and read O'Reilly Learning content from the terminal",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// The flag wins over GOREILLY_PROFILE, which config falls back
+		// to on its own.
+		if profileFlag != "" {
+			config.SetProfile(profileFlag)
+		}
+		// Bring on-disk state up to the current layout. A state dir
+		// written by a newer goreilly refuses to run at all.
+		if err := migrate.Run(); err != nil {
+			return err
+		}
+		level := "info"
+		if cfg, err := config.Load(); err == nil {
+			level = cfg.LogLevel
+		}
+		logging.Setup(level)
+		// Every log line names the command and profile it ran under, so
+		// a support grep can isolate one command's activity.
+		command := strings.TrimSpace(strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()))
+		if command == "" {
+			command = "tui"
+		}
+		// The run id correlates one invocation's lines in the persistent
+		// log; `goreilly report` tells users to quote it.
+		scope := []any{"command", command, "run", runID()}
+		if p := config.Profile(); p != "" {
+			scope = append(scope, "profile", p)
+		}
+		logging.Scope(scope...)
+		if err := validateOutput(); err != nil {
+			return err
+		}
+		// Structured output never carries ANSI or emoji, TTY or not.
+		style.Apply(style.Detect(noColorFlag || machineOutput(), noEmojiFlag || machineOutput()))
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return tui.Run()
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&profileFlag, "profile", "", "named profile scoping config, tokens, and the catalog (or GOREILLY_PROFILE)")
+	rootCmd.PersistentFlags().BoolVar(&noColorFlag, "no-color", false, "disable colored output (also via NO_COLOR or a non-TTY)")
+	rootCmd.PersistentFlags().BoolVar(&noEmojiFlag, "no-emoji", false, "disable emoji in output")
+	rootCmd.PersistentFlags().StringVar(&outputFlag, "output", "table", "output format: table, json, or ndjson")
+	rootCmd.PersistentFlags().BoolVar(&insecureFlag, "insecure", false, "skip TLS certificate verification (or the tls.insecure config key)")
+	rootCmd.PersistentFlags().StringVar(&jwtFlag, "jwt", "", "session token for this run only, outranking stored credentials")
+	rootCmd.PersistentFlags().Float64Var(&rpsFlag, "rps", 0, "requests per second per host (overrides requests_per_second)")
+	rootCmd.PersistentFlags().IntVar(&burstFlag, "burst", 0, "rate limiter burst size (overrides the burst config key)")
+	rootCmd.PersistentFlags().IntVar(&maxRetriesFlag, "max-retries", -1, "retries per failed request (overrides max_retries)")
+}
+
+// Execute runs the root command.
+func Execute() error {
+	return rootCmd.Execute()
+}
+
+// newOreillyService builds the API service with client options derived
+// from configuration (proxy settings, etc.).
+func newOreillyService(cfg *config.Config) *oreilly.Service {
+	var opts []client.Option
+	// Rate limiting and retries come from config, with the persistent
+	// flags outranking it for one run.
+	rps, burst := 0.0, 0
+	retry := client.DefaultRetryPolicy
+	if cfg != nil {
+		rps, burst = cfg.RequestsPerSecond, cfg.Burst
+		retry.MaxRetries = cfg.MaxRetries
+		if cfg.RetryBaseDelay > 0 {
+			retry.BaseDelay = cfg.RetryBaseDelay
+		}
+		if cfg.RetryMaxDelay > 0 {
+			retry.MaxDelay = cfg.RetryMaxDelay
+		}
+	}
+	if rpsFlag > 0 {
+		rps = rpsFlag
+	}
+	if burstFlag > 0 {
+		burst = burstFlag
+	}
+	if maxRetriesFlag >= 0 {
+		retry.MaxRetries = maxRetriesFlag
+	}
+	if rps > 0 {
+		if burst <= 0 {
+			burst = 1
+		}
+		opts = append(opts, client.WithRateLimit(rps, burst))
+	}
+	opts = append(opts, client.WithRetryPolicy(retry))
+	if cfg != nil {
+		if insecureFlag || cfg.TLS.Insecure || cfg.TLS.CAFile != "" {
+			opts = append(opts, client.WithTLS(insecureFlag || cfg.TLS.Insecure, cfg.TLS.CAFile))
+		}
+		switch {
+		case cfg.Proxy.SOCKS5 != "":
+			opts = append(opts, client.WithProxy(cfg.Proxy.SOCKS5))
+		case cfg.Proxy.HTTP != "":
+			opts = append(opts, client.WithProxy(cfg.Proxy.HTTP))
+		}
+		if cfg.Proxy.NoProxy != "" {
+			opts = append(opts, client.WithNoProxy(cfg.Proxy.NoProxy))
+		}
+		// A malformed limit_rate is ignored rather than blocking every
+		// command; the download flag parses strictly.
+		if rate, err := client.ParseRate(cfg.LimitRate); err == nil && rate > 0 {
+			opts = append(opts, client.WithBodyThrottle(rate))
+		}
+	}
+	// Revalidate metadata and search responses instead of re-fetching
+	// them on every command.
+	opts = append(opts, client.WithResponseCache(client.NewResponseCache(filepath.Join(config.DataDir(), "httpcache"))))
+	// Requests carry the stored token automatically and survive a token
+	// rotated mid-session.
+	return oreilly.NewAuthenticatedService(newAuthService(), opts...)
+}
+
+// newAuthService builds the auth service with the run-scoped --jwt
+// override applied.
+func newAuthService() *auth.Service {
+	svc := auth.NewService()
+	svc.FlagJWT = jwtFlag
+	return svc
+}
@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tuannvm/koreilly/internal/catalog"
+	"github.com/tuannvm/koreilly/internal/config"
+	"github.com/tuannvm/koreilly/internal/epub"
+	"github.com/tuannvm/koreilly/internal/manifest"
+)
+
+var scanFlags struct {
+	dir string
+}
+
+var libraryScanCmd = &cobra.Command{
+	Use:   "scan",
+	Short: "Adopt EPUB and PDF files added outside goreilly into the library",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		dir := scanFlags.dir
+		if dir == "" {
+			dir = cfg.OutputDir
+		}
+
+		cat, err := catalog.Open(catalog.DefaultPath())
+		if err != nil {
+			return err
+		}
+		defer cat.Close()
+		records, err := cat.List()
+		if err != nil {
+			return err
+		}
+		known := make(map[string]bool, len(records))
+		for _, r := range records {
+			if abs, err := filepath.Abs(r.Path); err == nil {
+				known[abs] = true
+			}
+		}
+
+		var adopted, skipped int
+		err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			format := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+			if format != "epub" && format != "pdf" {
+				return nil
+			}
+			abs, err := filepath.Abs(path)
+			if err != nil {
+				return err
+			}
+			if known[abs] {
+				return nil
+			}
+			record, ok := adoptFile(path, format)
+			if !ok {
+				skipped++
+				fmt.Printf("Skipping %s (no readable metadata)\n", path)
+				return nil
+			}
+			if err := cat.Put(record); err != nil {
+				return err
+			}
+			adopted++
+			fmt.Printf("Adopted %s (%s)\n", path, record.Title)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d files adopted, %d skipped\n", adopted, skipped)
+		return nil
+	},
+}
+
+// adoptFile builds a catalog record for an externally added file. EPUBs
+// carry their own metadata; PDFs fall back to the file name.
+func adoptFile(path, format string) (catalog.Record, bool) {
+	record := catalog.Record{Path: path, Format: format}
+	if sum, err := manifest.FileSHA256(path); err == nil {
+		record.SHA256 = sum
+	}
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	switch format {
+	case "epub":
+		meta, err := epub.ReadMetadata(path)
+		if err != nil {
+			return record, false
+		}
+		record.Title = meta.Title
+		record.Authors = meta.Authors
+		record.ID = strings.TrimPrefix(meta.Identifier, "urn:orm:book:")
+		if strings.HasPrefix(meta.Identifier, "urn:isbn:") || isDigits(meta.Identifier) {
+			record.ISBN = strings.TrimPrefix(meta.Identifier, "urn:isbn:")
+		}
+	case "pdf":
+		record.Title = base
+	}
+	if record.Title == "" {
+		record.Title = base
+	}
+	if record.ID == "" {
+		record.ID = base
+	}
+	return record, true
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func init() {
+	libraryScanCmd.Flags().StringVarP(&scanFlags.dir, "dir", "d", "", "directory to scan (defaults to the configured output dir)")
+	libraryCmd.AddCommand(libraryScanCmd)
+}
@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tuannvm/koreilly/internal/auth"
+	"github.com/tuannvm/koreilly/internal/config"
+	"github.com/tuannvm/koreilly/internal/services/oreilly"
+)
+
+var searchFlags struct {
+	topics      []string
+	languages   []string
+	publishers  []string
+	formats     []string
+	levels      []string
+	issuedAfter string
+	sort        string
+	page        int
+	all         bool
+	table       tableFlags
+}
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search the O'Reilly library",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jwt, err := auth.NewService().Token()
+		if err != nil {
+			return err
+		}
+		opts := oreilly.SearchOptions{
+			Topics:     searchFlags.topics,
+			Languages:  searchFlags.languages,
+			Publishers: searchFlags.publishers,
+			Formats:    searchFlags.formats,
+			Levels:     searchFlags.levels,
+		}
+		if searchFlags.issuedAfter != "" {
+			t, err := time.Parse("2006-01-02", searchFlags.issuedAfter)
+			if err != nil {
+				return fmt.Errorf("--issued-after must be YYYY-MM-DD: %w", err)
+			}
+			opts.IssuedAfter = t
+		}
+		opts.Page = searchFlags.page
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		svc := newOreillyService(cfg)
+		var result *oreilly.SearchResult
+		if searchFlags.all {
+			result, err = svc.SearchBooksAll(cmd.Context(), jwt, strings.Join(args, " "), opts, 0)
+		} else {
+			result, err = svc.SearchBooks(cmd.Context(), jwt, strings.Join(args, " "), opts)
+		}
+		if err != nil {
+			return err
+		}
+		if err := sortResults(result.Results, searchFlags.sort); err != nil {
+			return err
+		}
+		if machineOutput() {
+			return emitStructured(result.Results)
+		}
+		fmt.Printf("%d results\n", result.Total)
+		return renderTable(searchFlags.table, result.Results, map[string]func(oreilly.Book) string{
+			"id":        func(b oreilly.Book) string { return b.ProductID },
+			"format":    func(b oreilly.Book) string { return b.Format },
+			"title":     func(b oreilly.Book) string { return b.Title },
+			"author":    func(b oreilly.Book) string { return strings.Join(b.Authors, ", ") },
+			"isbn":      func(b oreilly.Book) string { return b.ISBN },
+			"publisher": func(b oreilly.Book) string { return strings.Join(b.Publishers, ", ") },
+			"date":      func(b oreilly.Book) string { return b.Issued },
+			"level":     func(b oreilly.Book) string { return b.Level },
+			"rating": func(b oreilly.Book) string {
+				if b.AverageRating == 0 {
+					return ""
+				}
+				return fmt.Sprintf("%.1f", b.AverageRating)
+			},
+			"rank": func(b oreilly.Book) string {
+				if b.PopularityRank == 0 {
+					return ""
+				}
+				return fmt.Sprint(b.PopularityRank)
+			},
+		})
+	},
+}
+
+// sortResults reorders books by the --sort key: rating puts the
+// best-reviewed first, popularity the most-used. Unrated titles sink to
+// the bottom either way; the API's relevance order is kept among ties.
+func sortResults(books []oreilly.Book, key string) error {
+	switch key {
+	case "":
+		return nil
+	case "rating":
+		sort.SliceStable(books, func(i, j int) bool {
+			return books[i].AverageRating > books[j].AverageRating
+		})
+	case "popularity":
+		sort.SliceStable(books, func(i, j int) bool {
+			ri, rj := books[i].PopularityRank, books[j].PopularityRank
+			if ri == 0 {
+				return false
+			}
+			if rj == 0 {
+				return true
+			}
+			return ri < rj
+		})
+	default:
+		return fmt.Errorf("unknown --sort %q (want rating or popularity)", key)
+	}
+	return nil
+}
+
+func init() {
+	searchCmd.Flags().StringSliceVar(&searchFlags.topics, "topic", nil, "filter by topic (repeatable)")
+	searchCmd.Flags().StringSliceVar(&searchFlags.languages, "language", nil, "filter by language code (repeatable)")
+	searchCmd.Flags().StringSliceVar(&searchFlags.publishers, "publisher", nil, "filter by publisher (repeatable)")
+	searchCmd.Flags().StringSliceVar(&searchFlags.formats, "format", nil, "filter by format: book, video, audiobook (repeatable)")
+	searchCmd.Flags().StringSliceVar(&searchFlags.levels, "level", nil, "filter by level: beginner, intermediate, advanced (repeatable)")
+	searchCmd.Flags().StringVar(&searchFlags.issuedAfter, "issued-after", "", "only titles issued after this date (YYYY-MM-DD)")
+	searchCmd.Flags().StringVar(&searchFlags.sort, "sort", "", "reorder results: rating or popularity")
+	searchCmd.Flags().IntVar(&searchFlags.page, "page", 0, "fetch a specific result page (1-based)")
+	searchCmd.Flags().BoolVar(&searchFlags.all, "all", false, "follow result cursors and print every page")
+	searchFlags.table.addTableFlags(searchCmd, []string{"id", "format", "title", "author"})
+	searchCmd.MarkFlagsMutuallyExclusive("page", "all")
+	rootCmd.AddCommand(searchCmd)
+}
@@ -0,0 +1,289 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tuannvm/koreilly/internal/catalog"
+	"github.com/tuannvm/koreilly/internal/config"
+	"github.com/tuannvm/koreilly/internal/convert"
+	"github.com/tuannvm/koreilly/internal/delivery"
+	"github.com/tuannvm/koreilly/internal/manifest"
+	"github.com/tuannvm/koreilly/internal/style"
+	"github.com/tuannvm/koreilly/internal/util"
+)
+
+var sendFlags struct {
+	to     string
+	sendTo []string
+	force  bool
+}
+
+var sendCmd = &cobra.Command{
+	Use:   "send <book-id-or-file>",
+	Short: "Email a downloaded book to a Kindle address",
+	Long: `Send emails a book from the library (or any local file) to the
+configured Kindle address over SMTP. Every attempt is recorded in the
+delivery history, and a file that already reached the target is not
+re-sent without --force.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		targets, err := sendTargets(cfg)
+		if err != nil {
+			return err
+		}
+		cat, err := catalog.Open(catalog.DefaultPath())
+		if err != nil {
+			return err
+		}
+		defer cat.Close()
+		for _, device := range targets {
+			if err := sendOne(cmd.Context(), cfg, cat, args[0], device); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+// sendTargets resolves the flags into device profiles: --send-to names
+// devices from config, otherwise --to or the default Kindle address is
+// used as an address-only profile.
+func sendTargets(cfg *config.Config) ([]config.DeviceConfig, error) {
+	if len(sendFlags.sendTo) > 0 {
+		targets := make([]config.DeviceConfig, 0, len(sendFlags.sendTo))
+		for _, name := range sendFlags.sendTo {
+			device, ok := cfg.Delivery.Devices[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown device %q (configure it under delivery.devices)", name)
+			}
+			if device.Email == "" {
+				return nil, fmt.Errorf("device %q has no email configured", name)
+			}
+			targets = append(targets, device)
+		}
+		return targets, nil
+	}
+	target := sendFlags.to
+	if target == "" {
+		target = cfg.Delivery.KindleEmail
+	}
+	if target == "" {
+		return nil, fmt.Errorf("no target: pass --to or --send-to, or set delivery.kindle_email")
+	}
+	return []config.DeviceConfig{{Email: target}}, nil
+}
+
+// sendOne delivers source to one device, picking (or converting to) the
+// format it prefers, receipting the attempt, and parking failures in
+// the retry queue.
+func sendOne(ctx context.Context, cfg *config.Config, cat *catalog.Catalog, source string, device config.DeviceConfig) error {
+	target := device.Email
+	path, bookID, err := resolveForDevice(ctx, source, device)
+	if err != nil {
+		return err
+	}
+	if device.MaxSizeBytes > 0 {
+		if info, statErr := os.Stat(path); statErr == nil && info.Size() > device.MaxSizeBytes {
+			return fmt.Errorf("%s is %d bytes, over %s's %d byte limit", path, info.Size(), target, device.MaxSizeBytes)
+		}
+	}
+	sum, _ := manifest.FileSHA256(path)
+	if !sendFlags.force {
+		if done, err := cat.Delivered(sum, target); err != nil {
+			return err
+		} else if done {
+			fmt.Printf("%s already delivered to %s (re-run with --force to send anyway)\n", path, target)
+			return nil
+		}
+	}
+
+	sendErr := delivery.Send(cfg.Delivery, path, target, delivery.DataFor(cat, bookID, path))
+	receipt := catalog.Delivery{
+		BookID: bookID,
+		Path:   path,
+		SHA256: sum,
+		Target: target,
+		OK:     sendErr == nil,
+	}
+	if sendErr != nil {
+		receipt.Error = sendErr.Error()
+	}
+	if err := cat.DeliveryAppend(receipt); err != nil {
+		return err
+	}
+	if sendErr != nil {
+		// Transient mail trouble shouldn't lose the book: park it in
+		// the retry queue instead of failing the whole flow.
+		pending := catalog.PendingDelivery{
+			BookID: bookID, Path: path, SHA256: sum, Target: target,
+		}
+		if err := delivery.Enqueue(cat, pending, sendErr); err != nil {
+			return err
+		}
+		fmt.Printf("Send to %s failed (%v); queued for retry — run goreilly delivery retry to force an attempt\n", target, sendErr)
+		return nil
+	}
+	fmt.Printf("%sSent %s to %s\n", style.Emoji("📧 "), path, target)
+	return nil
+}
+
+// formatOrder returns the library formats to try for a target, best
+// first. Amazon addresses want Kindle formats; everything else reads
+// EPUB natively.
+func formatOrder(target string) []string {
+	if strings.Contains(target, "kindle") {
+		return []string{"azw3", "mobi", "epub", "pdf"}
+	}
+	return []string{"epub", "mobi", "azw3", "pdf"}
+}
+
+// resolveForDevice finds the best artifact for a device. An explicit
+// format preference wins outright: a library book lacking it is
+// converted from its EPUB on the fly and recorded, so the next send is
+// free.
+func resolveForDevice(ctx context.Context, source string, device config.DeviceConfig) (path, bookID string, err error) {
+	if device.Format == "" {
+		return resolveSendSource(source, formatOrder(device.Email))
+	}
+	path, bookID, err = resolveSendSource(source, []string{device.Format})
+	if err == nil {
+		return path, bookID, nil
+	}
+	epubPath, bookID, err := resolveSendSource(source, []string{"epub"})
+	if err != nil {
+		return "", "", fmt.Errorf("no %s or epub for %q in the library", device.Format, source)
+	}
+	switch device.Format {
+	case "mobi", "azw3":
+		path, err = convert.FromEPUB(ctx, epubPath, device.Format)
+	default:
+		path, err = convertFromEPUB(ctx, epubPath, device.Format)
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("converting for device: %w", err)
+	}
+	if bookID != "" {
+		if err := recordDownload(bookID, device.Format, path); err != nil {
+			return "", "", err
+		}
+	}
+	return path, bookID, nil
+}
+
+// resolveSendSource turns the argument into a file path: an existing
+// file is used directly, anything else is looked up in the catalog in
+// the given format order.
+func resolveSendSource(arg string, formats []string) (path, bookID string, err error) {
+	if _, statErr := os.Stat(arg); statErr == nil {
+		return arg, "", nil
+	}
+	cat, err := catalog.Open(catalog.DefaultPath())
+	if err != nil {
+		return "", "", err
+	}
+	defer cat.Close()
+	for _, format := range formats {
+		r, err := cat.Get(arg, format)
+		if err != nil {
+			return "", "", err
+		}
+		if r != nil {
+			if _, statErr := os.Stat(r.Path); statErr == nil {
+				return r.Path, r.ID, nil
+			}
+		}
+	}
+	return "", "", fmt.Errorf("%q is neither a file nor a library book", arg)
+}
+
+var deliveryTable tableFlags
+
+var deliveryCmd = &cobra.Command{
+	Use:   "delivery",
+	Short: "Inspect book deliveries",
+}
+
+var deliveryHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List every send attempt with its outcome",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cat, err := catalog.Open(catalog.DefaultPath())
+		if err != nil {
+			return err
+		}
+		defer cat.Close()
+		deliveries, err := cat.DeliveryList()
+		if err != nil {
+			return err
+		}
+		if machineOutput() {
+			return emitStructured(deliveries)
+		}
+		if len(deliveries) == 0 {
+			fmt.Println("No deliveries recorded.")
+			return nil
+		}
+		return renderTable(deliveryTable, deliveries, map[string]func(catalog.Delivery) string{
+			"sent":   func(d catalog.Delivery) string { return util.FormatDate(d.SentAt, "iso") },
+			"path":   func(d catalog.Delivery) string { return d.Path },
+			"target": func(d catalog.Delivery) string { return d.Target },
+			"result": func(d catalog.Delivery) string {
+				if d.OK {
+					return "ok"
+				}
+				return "failed"
+			},
+			"error": func(d catalog.Delivery) string { return d.Error },
+		})
+	},
+}
+
+var deliveryRetryFlags struct {
+	due bool
+}
+
+var deliveryRetryCmd = &cobra.Command{
+	Use:   "retry",
+	Short: "Attempt queued deliveries now",
+	Long: `Retry re-attempts deliveries that failed earlier. By default every
+queued delivery is tried immediately; --due respects each entry's
+backoff window instead.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		cat, err := catalog.Open(catalog.DefaultPath())
+		if err != nil {
+			return err
+		}
+		defer cat.Close()
+		res, err := delivery.ProcessPending(cfg.Delivery, cat, !deliveryRetryFlags.due)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Retried deliveries: %d sent, %d failed again, %d waiting for backoff\n", res.Sent, res.Failed, res.Deferred)
+		return nil
+	},
+}
+
+func init() {
+	sendCmd.Flags().StringVar(&sendFlags.to, "to", "", "target address (default delivery.kindle_email)")
+	sendCmd.Flags().StringSliceVar(&sendFlags.sendTo, "send-to", nil, "named devices from delivery.devices, e.g. kindle,boox")
+	sendCmd.Flags().BoolVar(&sendFlags.force, "force", false, "send even if the same file already reached the target")
+	deliveryTable.addTableFlags(deliveryHistoryCmd, []string{"sent", "path", "target", "result"})
+	deliveryRetryCmd.Flags().BoolVar(&deliveryRetryFlags.due, "due", false, "only attempt entries whose backoff window has passed")
+	deliveryCmd.AddCommand(deliveryHistoryCmd, deliveryRetryCmd)
+	rootCmd.AddCommand(sendCmd, deliveryCmd)
+}
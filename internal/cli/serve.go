@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tuannvm/koreilly/internal/catalog"
+	"github.com/tuannvm/koreilly/internal/client"
+	"github.com/tuannvm/koreilly/internal/metrics"
+	"github.com/tuannvm/koreilly/internal/opds"
+)
+
+var serveFlags struct {
+	addr    string
+	metrics bool
+}
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve the local library as an OPDS catalog",
+	Long: `Serve exposes the downloaded library as an OPDS 1.2 feed at /opds
+with acquisition links and cover thumbnails, so e-reader apps on the
+same network can browse and download books directly.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cat, err := catalog.Open(catalog.DefaultPath())
+		if err != nil {
+			return err
+		}
+		defer cat.Close()
+		handler := http.Handler(opds.New(cat))
+		if serveFlags.metrics {
+			// The recorder is installed process-wide, so every client
+			// this long-lived server builds from here on reports in.
+			rec := metrics.NewPrometheus()
+			client.SetDefaultRecorder(rec)
+			mux := http.NewServeMux()
+			mux.Handle("/metrics", rec)
+			mux.Handle("/", handler)
+			handler = mux
+			fmt.Printf("Serving metrics on %s/metrics\n", serveFlags.addr)
+		}
+		fmt.Printf("Serving OPDS catalog on %s/opds\n", serveFlags.addr)
+		return http.ListenAndServe(serveFlags.addr, handler)
+	},
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveFlags.addr, "addr", ":8766", "listen address")
+	serveCmd.Flags().BoolVar(&serveFlags.metrics, "metrics", false, "expose Prometheus metrics at /metrics")
+	rootCmd.AddCommand(serveCmd)
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tuannvm/koreilly/internal/stats"
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show usage statistics (reading time, downloads)",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store := stats.NewStore()
+		totals, err := store.ReadingMinutesByBook()
+		if err != nil {
+			return err
+		}
+		if len(totals) == 0 {
+			fmt.Println("No reading sessions recorded yet.")
+			return nil
+		}
+		books := make([]string, 0, len(totals))
+		for b := range totals {
+			books = append(books, b)
+		}
+		sort.Slice(books, func(i, j int) bool { return totals[books[i]] > totals[books[j]] })
+		fmt.Println("Reading time per book:")
+		for _, b := range books {
+			fmt.Printf("  %6.0f min  %s\n", totals[b], b)
+		}
+		return nil
+	},
+}
+
+var statsExportFlags struct {
+	format string
+	bucket time.Duration
+	since  time.Duration
+	output string
+}
+
+var statsExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export time-bucketed usage series as CSV or JSON",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store := stats.NewStore()
+		since := time.Time{}
+		if statsExportFlags.since > 0 {
+			since = time.Now().Add(-statsExportFlags.since)
+		}
+		buckets, err := store.Series(since, statsExportFlags.bucket)
+		if err != nil {
+			return err
+		}
+		out := os.Stdout
+		if statsExportFlags.output != "" {
+			f, err := os.Create(statsExportFlags.output)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			out = f
+		}
+		switch statsExportFlags.format {
+		case "csv":
+			return stats.WriteCSV(out, buckets)
+		case "json":
+			return stats.WriteJSON(out, buckets)
+		default:
+			return fmt.Errorf("unknown format %q (want csv or json)", statsExportFlags.format)
+		}
+	},
+}
+
+func init() {
+	statsExportCmd.Flags().StringVar(&statsExportFlags.format, "format", "csv", "output format: csv or json")
+	statsExportCmd.Flags().DurationVar(&statsExportFlags.bucket, "bucket", 24*time.Hour, "bucket width (e.g. 1h, 24h)")
+	statsExportCmd.Flags().DurationVar(&statsExportFlags.since, "since", 0, "only include events from the last duration (e.g. 720h)")
+	statsExportCmd.Flags().StringVarP(&statsExportFlags.output, "output", "o", "", "write to file instead of stdout")
+	statsCmd.AddCommand(statsExportCmd)
+	rootCmd.AddCommand(statsCmd)
+}
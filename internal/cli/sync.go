@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tuannvm/koreilly/internal/auth"
+	"github.com/tuannvm/koreilly/internal/catalog"
+	"github.com/tuannvm/koreilly/internal/config"
+	"github.com/tuannvm/koreilly/internal/manifest"
+	"github.com/tuannvm/koreilly/internal/util"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync <manifest.yaml>",
+	Short: "Download the exact books pinned in a team manifest",
+	Long: `Sync downloads every book listed in a YAML manifest, verifying the
+pinned SHA-256 hashes when present. Keep the manifest in git so a team
+shares one reproducible reference library definition.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, err := manifest.Load(args[0])
+		if err != nil {
+			return err
+		}
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		jwt, err := auth.NewService().Token()
+		if err != nil {
+			return err
+		}
+
+		cat, err := catalog.Open(catalog.DefaultPath())
+		if err != nil {
+			return err
+		}
+		report, err := buildSyncReport(m, cat)
+		cat.Close()
+		if err != nil {
+			return err
+		}
+		report.print()
+
+		svc := newOreillyService(cfg)
+		if report.changes() > 0 {
+			var estimated int64
+			var unknown int
+			for _, entry := range append(append([]manifest.Entry{}, report.toAdd...), report.toUpdate...) {
+				size, err := svc.EstimateBookSize(cmd.Context(), jwt, entry.ID)
+				if err != nil || size == 0 {
+					unknown++
+					continue
+				}
+				estimated += size
+			}
+			line := fmt.Sprintf("Estimated download size: %s", util.FormatBytesIn(estimated, cfg.Format.Sizes))
+			if unknown > 0 {
+				line += fmt.Sprintf(" (+%d title(s) of unknown size)", unknown)
+			}
+			fmt.Println(line)
+			if cfg.SizeWarnBytes > 0 && estimated > cfg.SizeWarnBytes {
+				fmt.Printf("Warning: estimate exceeds the %s threshold (size_warn_bytes)\n",
+					util.FormatBytesIn(cfg.SizeWarnBytes, cfg.Format.Sizes))
+			}
+		}
+
+		ok, err := confirmSync(report, syncFlags.yes)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted.")
+			return nil
+		}
+		if report.changes() == 0 {
+			fmt.Println("Nothing to do.")
+			return nil
+		}
+
+		var failures, skips int
+		for _, entry := range m.Books {
+			fmt.Printf("Syncing %s...\n", entry.ID)
+			path, _, skipped, err := downloadBookTo(cmd.Context(), cfg, svc, jwt, entry.ID, cfg.OutputDir)
+			if skipped {
+				skips++
+			}
+			if err != nil {
+				fmt.Printf("  FAILED: %v\n", err)
+				failures++
+				continue
+			}
+			sum, err := manifest.FileSHA256(path)
+			if err != nil {
+				fmt.Printf("  FAILED hashing: %v\n", err)
+				failures++
+				continue
+			}
+			switch {
+			case entry.SHA256 == "":
+				fmt.Printf("  ok (unpinned, sha256: %s)\n", sum)
+			case entry.SHA256 == sum:
+				fmt.Printf("  ok (hash verified)\n")
+			default:
+				fmt.Printf("  HASH MISMATCH: manifest %s, got %s\n", entry.SHA256, sum)
+				failures++
+			}
+		}
+		if failures > 0 {
+			return fmt.Errorf("%d of %d manifest entries failed", failures, len(m.Books))
+		}
+		if skips > 0 {
+			fmt.Printf("Synced %d books (%d already downloaded)\n", len(m.Books), skips)
+		} else {
+			fmt.Printf("Synced %d books\n", len(m.Books))
+		}
+		return nil
+	},
+}
+
+var syncFlags struct {
+	yes bool
+}
+
+func init() {
+	syncCmd.Flags().BoolVarP(&syncFlags.yes, "yes", "y", false, "skip the confirmation prompt")
+	rootCmd.AddCommand(syncCmd)
+}
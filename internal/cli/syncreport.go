@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tuannvm/koreilly/internal/catalog"
+	"github.com/tuannvm/koreilly/internal/manifest"
+)
+
+// syncReport is the diff between a manifest and the local catalog,
+// computed before any download starts.
+type syncReport struct {
+	toAdd    []manifest.Entry // not in the catalog (or file missing)
+	toUpdate []manifest.Entry // present but pinned hash differs
+	toPrune  []catalog.Record // in the catalog but not in the manifest
+	upToDate int
+}
+
+func buildSyncReport(m *manifest.Manifest, cat *catalog.Catalog) (*syncReport, error) {
+	report := &syncReport{}
+	inManifest := make(map[string]bool)
+	for _, entry := range m.Books {
+		inManifest[entry.ID] = true
+		rec, err := cat.Get(entry.ID, "epub")
+		if err != nil {
+			return nil, err
+		}
+		if rec == nil {
+			report.toAdd = append(report.toAdd, entry)
+			continue
+		}
+		if _, err := os.Stat(rec.Path); err != nil {
+			report.toAdd = append(report.toAdd, entry)
+			continue
+		}
+		if entry.SHA256 != "" && entry.SHA256 != rec.SHA256 {
+			report.toUpdate = append(report.toUpdate, entry)
+			continue
+		}
+		report.upToDate++
+	}
+	records, err := cat.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, rec := range records {
+		if !inManifest[rec.ID] {
+			report.toPrune = append(report.toPrune, rec)
+		}
+	}
+	return report, nil
+}
+
+// changes is the number of downloads the sync would perform.
+func (r *syncReport) changes() int {
+	return len(r.toAdd) + len(r.toUpdate)
+}
+
+func (r *syncReport) print() {
+	fmt.Printf("Sync plan: %d to add, %d to update, %d candidate(s) to prune, %d up to date\n",
+		len(r.toAdd), len(r.toUpdate), len(r.toPrune), r.upToDate)
+	for _, e := range r.toAdd {
+		fmt.Printf("  + %s %s\n", e.ID, e.Title)
+	}
+	for _, e := range r.toUpdate {
+		fmt.Printf("  ~ %s %s (pinned hash differs)\n", e.ID, e.Title)
+	}
+	for _, rec := range r.toPrune {
+		fmt.Printf("  - %s %s (not in manifest; remove manually)\n", rec.ID, rec.Title)
+	}
+}
+
+// confirmSync asks the user to proceed unless --yes was given. Large
+// syncs always require explicit confirmation.
+func confirmSync(r *syncReport, assumeYes bool) (bool, error) {
+	if r.changes() == 0 {
+		return true, nil
+	}
+	if assumeYes {
+		return true, nil
+	}
+	fmt.Printf("Proceed with %d download(s)? [y/N] ", r.changes())
+	reader := bufio.NewReader(os.Stdin)
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return false, fmt.Errorf("reading confirmation: %w", err)
+	}
+	answer := strings.ToLower(strings.TrimSpace(line))
+	return answer == "y" || answer == "yes", nil
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// tableFlags holds the shared rendering options of list-style commands.
+type tableFlags struct {
+	columns  []string
+	noHeader bool
+}
+
+// addTableFlags registers --columns and --no-header on a list command.
+func (t *tableFlags) addTableFlags(cmd *cobra.Command, defaults []string) {
+	cmd.Flags().StringSliceVar(&t.columns, "columns", defaults, "columns to print, in order")
+	cmd.Flags().BoolVar(&t.noHeader, "no-header", false, "omit the header row (for cut/awk pipelines)")
+}
+
+// renderTable prints rows as aligned columns. fields maps a column name
+// to its value extractor; unknown column names are an error so typos
+// don't silently print empty columns.
+func renderTable[T any](t tableFlags, rows []T, fields map[string]func(T) string) error {
+	for _, col := range t.columns {
+		if _, ok := fields[col]; !ok {
+			known := make([]string, 0, len(fields))
+			for name := range fields {
+				known = append(known, name)
+			}
+			sort.Strings(known)
+			return fmt.Errorf("unknown column %q (available: %s)", col, strings.Join(known, ", "))
+		}
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	if !t.noHeader {
+		fmt.Fprintln(w, strings.ToUpper(strings.Join(t.columns, "\t")))
+	}
+	for _, row := range rows {
+		cells := make([]string, len(t.columns))
+		for i, col := range t.columns {
+			cells[i] = fields[col](row)
+		}
+		fmt.Fprintln(w, strings.Join(cells, "\t"))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var tokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Inspect the session token",
+}
+
+var tokenShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Decode the stored orm-jwt and show its claims",
+	Long: `Show decodes the current session token locally (it is inspected, not
+verified) and prints its claims, including the real expiry the server
+issued rather than an assumed lifetime.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		info, err := newAuthService().TokenInfo()
+		if err != nil {
+			return err
+		}
+		if machineOutput() {
+			return emitOne(info)
+		}
+		if info.Subject != "" {
+			fmt.Printf("Subject:      %s\n", info.Subject)
+		}
+		if info.Email != "" {
+			fmt.Printf("Email:        %s\n", info.Email)
+		}
+		if info.Issuer != "" {
+			fmt.Printf("Issuer:       %s\n", info.Issuer)
+		}
+		if info.AccountType != "" {
+			fmt.Printf("Account:      %s\n", info.AccountType)
+		}
+		if len(info.Entitlements) > 0 {
+			fmt.Printf("Entitlements: %s\n", strings.Join(info.Entitlements, ", "))
+		}
+		if !info.IssuedAt.IsZero() {
+			fmt.Printf("Issued:       %s\n", info.IssuedAt.Format("2006-01-02 15:04:05"))
+		}
+		switch {
+		case info.ExpiresAt.IsZero():
+			fmt.Println("Expires:      unknown (no exp claim)")
+		case time.Now().After(info.ExpiresAt):
+			fmt.Printf("Expires:      %s (expired)\n", info.ExpiresAt.Format("2006-01-02 15:04:05"))
+		default:
+			fmt.Printf("Expires:      %s (in %s)\n", info.ExpiresAt.Format("2006-01-02 15:04:05"),
+				time.Until(info.ExpiresAt).Round(time.Minute))
+		}
+		return nil
+	},
+}
+
+func init() {
+	tokenCmd.AddCommand(tokenShowCmd)
+	rootCmd.AddCommand(tokenCmd)
+}
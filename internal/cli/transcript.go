@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tuannvm/koreilly/internal/config"
+	"github.com/tuannvm/koreilly/internal/services/oreilly"
+	"github.com/tuannvm/koreilly/internal/transcript"
+	"github.com/tuannvm/koreilly/internal/util"
+)
+
+var transcriptFlags struct {
+	format string
+	dir    string
+}
+
+var transcriptCmd = &cobra.Command{
+	Use:   "transcript <course-id-or-url>",
+	Short: "Export a video course's transcripts",
+	Long: `Transcript fetches the captions of every lesson in a video course and
+writes one file per lesson (plain text, SRT, or VTT) plus a combined
+transcript.md for the whole course, so course content can be searched
+offline.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := oreilly.ResolveID(args[0])
+		if err != nil {
+			return err
+		}
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		jwt, err := newAuthService().Token()
+		if err != nil {
+			return err
+		}
+		svc := newOreillyService(cfg)
+		lessons, err := svc.ListVideoLessons(cmd.Context(), jwt, id)
+		if err != nil {
+			return err
+		}
+
+		outDir := transcriptFlags.dir
+		if outDir == "" {
+			outDir = filepath.Join(cfg.OutputDir, util.SanitizeFilename(id), "transcripts")
+		}
+		if err := os.MkdirAll(outDir, 0o755); err != nil {
+			return err
+		}
+
+		var combined strings.Builder
+		fmt.Fprintf(&combined, "# Transcript — %s\n", id)
+		section := ""
+		written := 0
+		for _, lesson := range lessons {
+			if lesson.CaptionsURL == "" {
+				continue
+			}
+			data, err := svc.FetchCaptions(cmd.Context(), jwt, lesson.CaptionsURL)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Skipping %s: %v\n", lesson.Title, err)
+				continue
+			}
+			cues := transcript.ParseVTT(data)
+			if len(cues) == 0 {
+				continue
+			}
+
+			var out []byte
+			ext := ""
+			switch transcriptFlags.format {
+			case "text":
+				out, ext = transcript.Text(cues), ".txt"
+			case "srt":
+				out, ext = transcript.SRT(cues), ".srt"
+			case "vtt":
+				out, ext = transcript.VTT(cues), ".vtt"
+			default:
+				return fmt.Errorf("unknown --format %q (want text, srt, or vtt)", transcriptFlags.format)
+			}
+			base := fmt.Sprintf("%02d-%02d %s", lesson.SectionNum, lesson.LessonNum, util.SanitizeFilename(lesson.Title))
+			if err := os.WriteFile(filepath.Join(outDir, base+ext), out, 0o644); err != nil {
+				return err
+			}
+			written++
+
+			if lesson.Section != section {
+				section = lesson.Section
+				fmt.Fprintf(&combined, "\n## %s\n", section)
+			}
+			fmt.Fprintf(&combined, "\n### %02d-%02d %s\n\n%s", lesson.SectionNum, lesson.LessonNum, lesson.Title, transcript.Text(cues))
+		}
+		if written == 0 {
+			return fmt.Errorf("course %s has no captions", id)
+		}
+		if err := os.WriteFile(filepath.Join(outDir, "transcript.md"), []byte(combined.String()), 0o644); err != nil {
+			return err
+		}
+		fmt.Printf("Wrote %d lesson transcripts and transcript.md to %s\n", written, outDir)
+		return nil
+	},
+}
+
+func init() {
+	transcriptCmd.Flags().StringVar(&transcriptFlags.format, "format", "text", "per-lesson format: text, srt, or vtt")
+	transcriptCmd.Flags().StringVar(&transcriptFlags.dir, "dir", "", "output directory (defaults under the output dir)")
+	rootCmd.AddCommand(transcriptCmd)
+}
@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tuannvm/koreilly/internal/auth"
+	"github.com/tuannvm/koreilly/internal/catalog"
+	"github.com/tuannvm/koreilly/internal/config"
+	"github.com/tuannvm/koreilly/internal/downloader"
+	"github.com/tuannvm/koreilly/internal/epub"
+	"github.com/tuannvm/koreilly/internal/manifest"
+)
+
+var verifyFlags struct {
+	redownload bool
+}
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check library files for corruption, optionally re-downloading bad ones",
+	Long: `Verify re-checks every library file: the recorded SHA-256 must still
+match, EPUBs must have a sound zip structure with the mimetype entry
+first, and PDFs must carry their header and EOF markers. With
+--redownload, corrupt or missing books are fetched again.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cat, err := catalog.Open(catalog.DefaultPath())
+		if err != nil {
+			return err
+		}
+		records, err := cat.List()
+		if err != nil {
+			cat.Close()
+			return err
+		}
+		cat.Close()
+
+		var ok int
+		var bad []catalog.Record
+		for _, r := range records {
+			if err := verifyRecord(r); err != nil {
+				fmt.Printf("BAD  %s: %v\n", r.Path, err)
+				bad = append(bad, r)
+				continue
+			}
+			ok++
+		}
+		fmt.Printf("%d files ok, %d corrupt or missing\n", ok, len(bad))
+		if len(bad) == 0 || !verifyFlags.redownload {
+			return nil
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		jwt, err := auth.NewService().Token()
+		if err != nil {
+			return err
+		}
+		svc := newOreillyService(cfg)
+		for _, r := range bad {
+			if r.Format != "epub" {
+				fmt.Printf("Cannot re-download %s (%s); only EPUBs are re-fetched\n", r.Path, r.Format)
+				continue
+			}
+			// Drop the bad copy and its record so the downloader doesn't
+			// treat the book as already present.
+			_ = os.Remove(r.Path)
+			if err := func() error {
+				cat, err := catalog.Open(catalog.DefaultPath())
+				if err != nil {
+					return err
+				}
+				defer cat.Close()
+				return cat.Delete(r.ID, r.Format)
+			}(); err != nil {
+				return err
+			}
+			path, _, err := downloader.Book(cmd.Context(), cfg, svc, jwt, r.ID, cfg.OutputDir, stderrProgress)
+			if err != nil {
+				return fmt.Errorf("re-downloading %s: %w", r.ID, err)
+			}
+			fmt.Printf("Re-downloaded %s to %s\n", r.ID, path)
+		}
+		return nil
+	},
+}
+
+// verifyRecord checks one library file's integrity.
+func verifyRecord(r catalog.Record) error {
+	if _, err := os.Stat(r.Path); err != nil {
+		return fmt.Errorf("file missing")
+	}
+	if r.SHA256 != "" {
+		sum, err := manifest.FileSHA256(r.Path)
+		if err != nil {
+			return err
+		}
+		if sum != r.SHA256 {
+			return fmt.Errorf("checksum mismatch")
+		}
+	}
+	switch r.Format {
+	case "epub":
+		return epub.Validate(r.Path)
+	case "pdf":
+		return validatePDF(r.Path)
+	}
+	return nil
+}
+
+// validatePDF checks the PDF header and the EOF marker near the end of
+// the file, which truncated downloads lack.
+func validatePDF(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	header := make([]byte, 5)
+	if _, err := f.Read(header); err != nil || !bytes.Equal(header, []byte("%PDF-")) {
+		return fmt.Errorf("missing %%PDF header")
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	tail := make([]byte, 1024)
+	off := info.Size() - int64(len(tail))
+	if off < 0 {
+		off = 0
+	}
+	n, _ := f.ReadAt(tail, off)
+	if !bytes.Contains(tail[:n], []byte("%%EOF")) {
+		return fmt.Errorf("missing %%%%EOF trailer")
+	}
+	return nil
+}
+
+func init() {
+	verifyCmd.Flags().BoolVar(&verifyFlags.redownload, "redownload", false, "re-download corrupt or missing EPUBs")
+	rootCmd.AddCommand(verifyCmd)
+}
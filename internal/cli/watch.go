@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tuannvm/koreilly/internal/catalog"
+	"github.com/tuannvm/koreilly/internal/config"
+	"github.com/tuannvm/koreilly/internal/delivery"
+	"github.com/tuannvm/koreilly/internal/notify"
+	"github.com/tuannvm/koreilly/internal/services/oreilly"
+)
+
+var watchFlags struct {
+	topics   []string
+	query    string
+	interval time.Duration
+	webhook  string
+	email    string
+	desktop  bool
+	once     bool
+}
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Poll for new releases matching topics or a query and notify",
+	Long: `Watch periodically re-runs a search and alerts when titles appear that
+earlier polls had not seen. Seen titles are recorded in the catalog, so
+restarting the watcher does not re-announce old releases; the very
+first poll only records a baseline. Alerts go to the terminal, and
+optionally to a desktop notification, a webhook, or an email address.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if watchFlags.query == "" && len(watchFlags.topics) == 0 {
+			return fmt.Errorf("give --query and/or --topic something to watch")
+		}
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		svc := newOreillyService(cfg)
+		for {
+			if err := watchPoll(cmd.Context(), cfg, svc); err != nil {
+				// A failed poll (network blip, expired token) shouldn't
+				// kill a long-running watcher; the next tick retries.
+				fmt.Printf("poll failed: %v\n", err)
+			}
+			if watchFlags.once {
+				return nil
+			}
+			select {
+			case <-cmd.Context().Done():
+				return cmd.Context().Err()
+			case <-time.After(watchFlags.interval):
+			}
+		}
+	},
+}
+
+// watchPoll runs one search, records unseen titles, and sends alerts
+// for them (unless this poll established the baseline).
+func watchPoll(ctx context.Context, cfg *config.Config, svc *oreilly.Service) error {
+	jwt, err := newAuthService().Token()
+	if err != nil {
+		return err
+	}
+	result, err := svc.SearchBooksAll(ctx, jwt, watchFlags.query, oreilly.SearchOptions{Topics: watchFlags.topics}, 3)
+	if err != nil {
+		return err
+	}
+
+	cat, err := catalog.Open(catalog.DefaultPath())
+	if err != nil {
+		return err
+	}
+	defer cat.Close()
+
+	baseline, err := cat.SeenEmpty()
+	if err != nil {
+		return err
+	}
+	var fresh []oreilly.Book
+	for _, book := range result.Results {
+		if book.ProductID == "" {
+			continue
+		}
+		seen, err := cat.SeenHas(book.ProductID)
+		if err != nil {
+			return err
+		}
+		if seen {
+			continue
+		}
+		if err := cat.SeenAdd(book.ProductID, book.Title); err != nil {
+			return err
+		}
+		fresh = append(fresh, book)
+	}
+
+	stamp := time.Now().Format("15:04:05")
+	switch {
+	case baseline:
+		fmt.Printf("[%s] Recorded baseline of %d titles; future polls will alert on new ones.\n", stamp, len(fresh))
+		return nil
+	case len(fresh) == 0:
+		fmt.Printf("[%s] Nothing new.\n", stamp)
+		return nil
+	}
+
+	fmt.Printf("[%s] %d new titles:\n", stamp, len(fresh))
+	var lines []string
+	for _, book := range fresh {
+		line := book.Title
+		if len(book.Authors) > 0 {
+			line += " — " + strings.Join(book.Authors, ", ")
+		}
+		fmt.Println("  " + line)
+		lines = append(lines, line)
+	}
+	return watchNotify(ctx, cfg, fresh, lines)
+}
+
+// watchNotify fans the alert out to whichever channels are configured.
+// Channel failures are reported but don't abort the watcher.
+func watchNotify(ctx context.Context, cfg *config.Config, fresh []oreilly.Book, lines []string) error {
+	subject := fmt.Sprintf("goreilly: %d new titles", len(fresh))
+	body := strings.Join(lines, "\n")
+	if watchFlags.desktop {
+		if err := notify.Desktop(subject, body); err != nil {
+			fmt.Printf("  %v\n", err)
+		}
+	}
+	if watchFlags.webhook != "" {
+		payload := struct {
+			Text  string         `json:"text"`
+			Books []oreilly.Book `json:"books"`
+		}{Text: subject + "\n" + body, Books: fresh}
+		if err := notify.Webhook(ctx, watchFlags.webhook, payload); err != nil {
+			fmt.Printf("  %v\n", err)
+		}
+	}
+	if watchFlags.email != "" {
+		if err := delivery.SendText(cfg.Delivery, watchFlags.email, subject, body); err != nil {
+			fmt.Printf("  %v\n", err)
+		}
+	}
+	return nil
+}
+
+func init() {
+	watchCmd.Flags().StringSliceVar(&watchFlags.topics, "topic", nil, "topic to watch (repeatable)")
+	watchCmd.Flags().StringVar(&watchFlags.query, "query", "", "free-text query to watch")
+	watchCmd.Flags().DurationVar(&watchFlags.interval, "interval", 24*time.Hour, "how often to poll")
+	watchCmd.Flags().StringVar(&watchFlags.webhook, "webhook", "", "POST new-title alerts to this URL as JSON")
+	watchCmd.Flags().StringVar(&watchFlags.email, "email", "", "email alerts to this address via the delivery SMTP account")
+	watchCmd.Flags().BoolVar(&watchFlags.desktop, "desktop", false, "show a desktop notification for new titles")
+	watchCmd.Flags().BoolVar(&watchFlags.once, "once", false, "poll once and exit (for cron)")
+	rootCmd.AddCommand(watchCmd)
+}
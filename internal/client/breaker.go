@@ -0,0 +1,92 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Breaker thresholds: after breakerThreshold consecutive failures to a
+// host, requests to it fail fast for breakerCooldown before a single
+// trial request is let through.
+const (
+	breakerThreshold = 5
+	breakerCooldown  = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned (wrapped with the host) when a host's
+// circuit breaker is open; callers can errors.Is against it to back off
+// instead of hammering a struggling server.
+var ErrCircuitOpen = errors.New("circuit open after repeated failures")
+
+// hostState holds the limiter and breaker for one host, so a busy
+// api.oreilly.com cannot starve or trip learning.oreilly.com.
+type hostState struct {
+	limiter *rate.Limiter
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+// host returns (creating on first use) the state for a host, seeded
+// with the client's configured rate.
+func (c *Client) host(name string) *hostState {
+	c.hostsMu.Lock()
+	defer c.hostsMu.Unlock()
+	h, ok := c.hosts[name]
+	if !ok {
+		h = &hostState{limiter: rate.NewLimiter(rate.Limit(c.rps), c.burst)}
+		c.hosts[name] = h
+	}
+	return h
+}
+
+// allow reports whether a request may proceed, failing fast while the
+// breaker is open. Once the cool-down passes, one trial request is
+// allowed through; its outcome decides whether the circuit re-opens.
+func (h *hostState) allow(host string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.failures >= breakerThreshold {
+		if remaining := time.Until(h.openUntil); remaining > 0 {
+			return fmt.Errorf("%s: %w (retry in %s)", host, ErrCircuitOpen, remaining.Round(time.Second))
+		}
+		// Half-open: let this request probe, but re-open immediately if
+		// it fails too.
+		h.failures = breakerThreshold - 1
+	}
+	return nil
+}
+
+// recordFailure notes a transport error or 5xx; crossing the threshold
+// opens the circuit.
+func (h *hostState) recordFailure() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.failures++
+	if h.failures >= breakerThreshold {
+		h.openUntil = time.Now().Add(breakerCooldown)
+	}
+}
+
+// recordSuccess closes the circuit.
+func (h *hostState) recordSuccess() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.failures = 0
+}
+
+// noteRateLimited halves the host's request rate (with a floor) after a
+// 429, so sustained sessions converge on the server's limit.
+func (h *hostState) noteRateLimited() {
+	const floor = rate.Limit(0.2)
+	next := h.limiter.Limit() / 2
+	if next < floor {
+		next = floor
+	}
+	h.limiter.SetLimit(next)
+}
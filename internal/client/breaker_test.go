@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerOpensAfterConsecutiveFailures(t *testing.T) {
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	c := New(
+		WithRateLimit(1000, 1000),
+		WithRetryPolicy(RetryPolicy{MaxRetries: 0, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+	)
+	for i := 0; i < breakerThreshold; i++ {
+		resp, err := c.Get(context.Background(), srv.URL, nil)
+		if err != nil {
+			t.Fatalf("request %d: unexpected error %v", i, err)
+		}
+		resp.Body.Close()
+	}
+	if hits != breakerThreshold {
+		t.Fatalf("server saw %d requests, want %d", hits, breakerThreshold)
+	}
+
+	_, err := c.Get(context.Background(), srv.URL, nil)
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("got %v, want ErrCircuitOpen", err)
+	}
+	if hits != breakerThreshold {
+		t.Errorf("open circuit still reached the server (%d hits)", hits)
+	}
+}
+
+func TestCircuitBreakerResetsOnSuccess(t *testing.T) {
+	fail := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	c := New(
+		WithRateLimit(1000, 1000),
+		WithRetryPolicy(RetryPolicy{MaxRetries: 0, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+	)
+	for i := 0; i < breakerThreshold-1; i++ {
+		resp, err := c.Get(context.Background(), srv.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	fail = false
+	for i := 0; i < 3; i++ {
+		resp, err := c.Get(context.Background(), srv.URL, nil)
+		if err != nil {
+			t.Fatalf("after recovery: %v", err)
+		}
+		resp.Body.Close()
+	}
+}
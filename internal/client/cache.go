@@ -0,0 +1,154 @@
+package client
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxCacheBody is the largest response body the cache will keep; book
+// content is cached elsewhere, this layer is for metadata, TOCs, and
+// search results.
+const maxCacheBody = 4 << 20
+
+// ResponseCache is a disk-backed store of validated GET responses. It
+// remembers each URL's ETag and Last-Modified, sends them back as
+// conditional headers, and answers 304s from the stored copy — so
+// interactive sessions stop re-transferring unchanged metadata.
+type ResponseCache struct {
+	dir string
+}
+
+// NewResponseCache returns a cache rooted at dir, created on first use.
+func NewResponseCache(dir string) *ResponseCache {
+	return &ResponseCache{dir: dir}
+}
+
+// WithResponseCache attaches a validation cache to GET requests.
+func WithResponseCache(rc *ResponseCache) Option {
+	return func(c *Client) { c.cache = rc }
+}
+
+// cacheEntry is the metadata stored alongside each cached body.
+type cacheEntry struct {
+	URL          string      `json:"url"`
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"last_modified,omitempty"`
+	Header       http.Header `json:"header"`
+}
+
+func (rc *ResponseCache) paths(url string) (meta, body string) {
+	sum := sha256.Sum256([]byte(url))
+	key := hex.EncodeToString(sum[:16])
+	return filepath.Join(rc.dir, key+".json"), filepath.Join(rc.dir, key+".body")
+}
+
+// load returns the stored entry for url, or nil if there is none.
+func (rc *ResponseCache) load(url string) *cacheEntry {
+	metaPath, _ := rc.paths(url)
+	data, err := os.ReadFile(metaPath)
+	if err != nil {
+		return nil
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.URL != url {
+		return nil
+	}
+	return &entry
+}
+
+// condition adds If-None-Match / If-Modified-Since for url's stored
+// entry and returns it for a later 304 replay.
+func (rc *ResponseCache) condition(req *http.Request) *cacheEntry {
+	entry := rc.load(req.URL.String())
+	if entry == nil {
+		return nil
+	}
+	if entry.ETag != "" {
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+	if entry.LastModified != "" {
+		req.Header.Set("If-Modified-Since", entry.LastModified)
+	}
+	return entry
+}
+
+// cacheable reports whether resp is worth buffering for the cache: it
+// must carry a validator, declare a length within maxCacheBody, and be
+// a textual metadata type. Everything else — media segments, EPUB
+// chapters' images, bodies of unknown size — streams straight through,
+// so downloads are never slurped into memory and the body throttle
+// still meters the network rather than a bytes.Reader.
+func cacheable(resp *http.Response) bool {
+	if resp.Header.Get("Etag") == "" && resp.Header.Get("Last-Modified") == "" {
+		return false
+	}
+	if resp.ContentLength < 0 || resp.ContentLength > maxCacheBody {
+		return false
+	}
+	ct := resp.Header.Get("Content-Type")
+	return strings.HasPrefix(ct, "application/json") || strings.HasPrefix(ct, "text/")
+}
+
+// store keeps resp's body and validators, replacing the body with an
+// in-memory copy so the caller reads it as usual. Responses cacheable
+// rejects are left untouched and never buffered.
+func (rc *ResponseCache) store(resp *http.Response) {
+	if !cacheable(resp) {
+		return
+	}
+	url := resp.Request.URL.String()
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	// The declared length passed the cap; the actual bytes must too.
+	if err != nil || len(body) > maxCacheBody {
+		return
+	}
+	entry := cacheEntry{
+		URL:          url,
+		ETag:         resp.Header.Get("Etag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Header:       resp.Header,
+	}
+	if err := os.MkdirAll(rc.dir, 0o755); err != nil {
+		return
+	}
+	metaPath, bodyPath := rc.paths(url)
+	meta, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	// Body first so a crash between the writes leaves no dangling meta.
+	if err := os.WriteFile(bodyPath, body, 0o644); err != nil {
+		return
+	}
+	_ = os.WriteFile(metaPath, meta, 0o644)
+}
+
+// respond replays the stored copy for a 304, presenting it to the
+// caller as the 200 it validated.
+func (rc *ResponseCache) respond(req *http.Request, entry *cacheEntry) (*http.Response, error) {
+	_, bodyPath := rc.paths(req.URL.String())
+	body, err := os.ReadFile(bodyPath)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		Status:        http.StatusText(http.StatusOK),
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        entry.Header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
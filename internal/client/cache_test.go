@@ -0,0 +1,91 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResponseCacheRevalidates(t *testing.T) {
+	var hits, fullBodies int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fullBodies++
+		w.Header().Set("Etag", `"v1"`)
+		w.Write([]byte("payload"))
+	}))
+	defer srv.Close()
+
+	c := New(
+		WithRateLimit(1000, 1000),
+		WithResponseCache(NewResponseCache(t.TempDir())),
+	)
+	for i := 0; i < 3; i++ {
+		resp, err := c.Get(context.Background(), srv.URL, nil)
+		if err != nil {
+			t.Fatalf("get %d: %v", i, err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil || string(body) != "payload" {
+			t.Fatalf("get %d: body %q, err %v", i, body, err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("get %d: status %d", i, resp.StatusCode)
+		}
+	}
+	if hits != 3 {
+		t.Errorf("server saw %d requests, want 3", hits)
+	}
+	if fullBodies != 1 {
+		t.Errorf("server sent %d full bodies, want 1", fullBodies)
+	}
+}
+
+func TestResponseCacheSkipsUnvalidatable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("no validators here"))
+	}))
+	defer srv.Close()
+
+	rc := NewResponseCache(t.TempDir())
+	c := New(WithRateLimit(1000, 1000), WithResponseCache(rc))
+	resp, err := c.Get(context.Background(), srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if rc.load(srv.URL) != nil {
+		t.Error("response without Etag or Last-Modified was cached")
+	}
+}
+
+func TestResponseCacheSkipsMediaBodies(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Etag", `"v1"`)
+		w.Header().Set("Content-Type", "video/mp4")
+		w.Write([]byte("not really a video"))
+	}))
+	defer srv.Close()
+
+	rc := NewResponseCache(t.TempDir())
+	c := New(WithRateLimit(1000, 1000), WithResponseCache(rc))
+	resp, err := c.Get(context.Background(), srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil || string(body) != "not really a video" {
+		t.Fatalf("body %q, err %v", body, err)
+	}
+	if rc.load(srv.URL) != nil {
+		t.Error("media response was cached")
+	}
+}
@@ -0,0 +1,213 @@
+// Package client provides the HTTP client shared by all services that
+// talk to the O'Reilly platform: rate limiting, retries with backoff,
+// and a consistent User-Agent.
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const defaultUserAgent = "goreilly/1.0"
+
+// Client wraps an *http.Client with rate limiting and retry behaviour.
+type Client struct {
+	httpClient *http.Client
+	userAgent  string
+	retry      RetryPolicy
+	logger     *slog.Logger
+	cache      *ResponseCache
+	recorder   Recorder
+
+	// middlewares wrap the whole pipeline; see Use.
+	middlewares []Middleware
+
+	// Rate limiting and circuit breaking are per host; rps and burst
+	// seed each host's limiter.
+	rps     float64
+	burst   int
+	hostsMu sync.Mutex
+	hosts   map[string]*hostState
+
+	// bodyRate caps download bandwidth in bytes/sec; 0 means unlimited.
+	bodyRate int64
+
+	// proxy and TLS configuration; setupErr surfaces on the first
+	// request so option application stays infallible.
+	proxyURL string
+	noProxy  string
+	setupErr error
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient replaces the underlying *http.Client.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(ua string) Option {
+	return func(c *Client) { c.userAgent = ua }
+}
+
+// WithRateLimit sets the request rate applied to each host.
+func WithRateLimit(rps float64, burst int) Option {
+	return func(c *Client) { c.rps, c.burst = rps, burst }
+}
+
+// WithRetryPolicy replaces the retry policy.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) { c.retry = p }
+}
+
+// WithCookieJar attaches a cookie jar to the underlying *http.Client,
+// typically a PersistentJar so sessions survive between runs.
+func WithCookieJar(jar http.CookieJar) Option {
+	return func(c *Client) { c.httpClient.Jar = jar }
+}
+
+// WithBodyThrottle caps download bandwidth by wrapping response bodies
+// in a token-bucket limiter. Zero means unlimited.
+func WithBodyThrottle(bytesPerSec int64) Option {
+	return func(c *Client) { c.bodyRate = bytesPerSec }
+}
+
+// WithLogger replaces the logger (slog.Default otherwise). Callers are
+// expected to pass a redacting logger; the client never logs header
+// values itself.
+func WithLogger(l *slog.Logger) Option {
+	return func(c *Client) { c.logger = l }
+}
+
+// New returns a Client with sane defaults: 2 req/s per host, burst of
+// 4, and the default retry policy.
+func New(opts ...Option) *Client {
+	c := &Client{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		userAgent:  defaultUserAgent,
+		rps:        2,
+		burst:      4,
+		hosts:      make(map[string]*hostState),
+		retry:      DefaultRetryPolicy,
+		logger:     slog.Default(),
+		recorder:   getDefaultRecorder(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Get issues a GET request to url with the given extra headers.
+func (c *Client) Get(ctx context.Context, url string, headers http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("building request for %s: %w", url, err)
+	}
+	for k, vs := range headers {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	return c.chain().RoundTrip(req)
+}
+
+// Head issues a HEAD request to url with the given extra headers.
+func (c *Client) Head(ctx context.Context, url string, headers http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("building request for %s: %w", url, err)
+	}
+	for k, vs := range headers {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	return c.chain().RoundTrip(req)
+}
+
+// Post issues a POST with a JSON body to url with the given extra
+// headers.
+func (c *Client) Post(ctx context.Context, url string, headers http.Header, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("building request for %s: %w", url, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, vs := range headers {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	return c.chain().RoundTrip(req)
+}
+
+// Do issues an arbitrary request through the middleware and retry
+// pipeline.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	return c.chain().RoundTrip(req)
+}
+
+// roundTripOnce is the innermost link of the chain: one attempt through
+// the host limiter, circuit breaker, validation cache, and throttle.
+func (c *Client) roundTripOnce(req *http.Request) (*http.Response, error) {
+	if c.setupErr != nil {
+		return nil, c.setupErr
+	}
+	h := c.host(req.URL.Host)
+	if err := h.allow(req.URL.Host); err != nil {
+		return nil, err
+	}
+	waitStart := time.Now()
+	if err := h.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	if c.recorder != nil {
+		if wait := time.Since(waitStart); wait > time.Millisecond {
+			c.recorder.RateLimitWait(req.URL.Host, wait)
+		}
+	}
+	c.logger.Debug("request", "method", req.Method, "url", req.URL.String())
+	var cached *cacheEntry
+	if c.cache != nil && req.Method == http.MethodGet {
+		cached = c.cache.condition(req)
+	}
+	attemptStart := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if c.recorder != nil {
+		status := 0
+		if err == nil {
+			status = resp.StatusCode
+		}
+		c.recorder.Request(req.URL.Host, status, time.Since(attemptStart))
+	}
+	if err != nil || resp.StatusCode >= http.StatusInternalServerError {
+		h.recordFailure()
+	} else {
+		h.recordSuccess()
+	}
+	if err == nil && c.cache != nil && req.Method == http.MethodGet {
+		if resp.StatusCode == http.StatusNotModified && cached != nil {
+			c.logger.Debug("cache revalidated", "url", req.URL.String())
+			resp.Body.Close()
+			return c.cache.respond(req, cached)
+		}
+		if resp.StatusCode == http.StatusOK {
+			c.cache.store(resp)
+		}
+	}
+	if err == nil && c.bodyRate > 0 && resp.Body != nil {
+		resp.Body = newThrottledBody(req.Context(), resp.Body, c.bodyRate)
+	}
+	if err == nil {
+		c.instrumentBody(req, resp)
+	}
+	return resp, err
+}
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// PersistentJar is an http.CookieJar backed by a JSON file, so session
+// cookies survive between runs instead of forcing a fresh login each
+// time. The file is written with owner-only permissions on every
+// change, which doubles as the flush on exit.
+type PersistentJar struct {
+	mu      sync.Mutex
+	jar     *cookiejar.Jar
+	path    string
+	entries map[string][]*http.Cookie // keyed by scheme://host
+}
+
+// NewPersistentJar loads (or creates) the jar file at path.
+func NewPersistentJar(path string) (*PersistentJar, error) {
+	jar, err := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+	if err != nil {
+		return nil, err
+	}
+	p := &PersistentJar{jar: jar, path: path, entries: map[string][]*http.Cookie{}}
+	if err := p.load(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *PersistentJar) load() error {
+	data, err := os.ReadFile(p.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &p.entries); err != nil {
+		// A corrupt jar just means logging in again; don't fail startup.
+		p.entries = map[string][]*http.Cookie{}
+		return nil
+	}
+	now := time.Now()
+	for key, cookies := range p.entries {
+		u, err := url.Parse(key)
+		if err != nil {
+			continue
+		}
+		fresh := cookies[:0]
+		for _, c := range cookies {
+			if !c.Expires.IsZero() && c.Expires.Before(now) {
+				continue
+			}
+			fresh = append(fresh, c)
+		}
+		p.entries[key] = fresh
+		p.jar.SetCookies(u, fresh)
+	}
+	return nil
+}
+
+// flush writes the jar to disk; callers hold the mutex.
+func (p *PersistentJar) flush() {
+	if err := os.MkdirAll(filepath.Dir(p.path), 0o755); err != nil {
+		return
+	}
+	data, err := json.MarshalIndent(p.entries, "", "  ")
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(p.path, data, 0o600)
+}
+
+// SetCookies implements http.CookieJar, recording the cookies for
+// persistence as well as normal jar matching.
+func (p *PersistentJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	p.jar.SetCookies(u, cookies)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	key := u.Scheme + "://" + u.Host
+	kept := p.entries[key][:0]
+	for _, existing := range p.entries[key] {
+		replaced := false
+		for _, c := range cookies {
+			if c.Name == existing.Name {
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			kept = append(kept, existing)
+		}
+	}
+	now := time.Now()
+	for _, c := range cookies {
+		if c.MaxAge < 0 || (!c.Expires.IsZero() && c.Expires.Before(now)) {
+			continue
+		}
+		kept = append(kept, c)
+	}
+	p.entries[key] = kept
+	p.flush()
+}
+
+// Cookies implements http.CookieJar.
+func (p *PersistentJar) Cookies(u *url.URL) []*http.Cookie {
+	return p.jar.Cookies(u)
+}
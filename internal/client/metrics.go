@@ -0,0 +1,69 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Recorder receives instrumentation events from the client pipeline:
+// request outcomes, retries, limiter waits, and body bytes read.
+// Implementations must be safe for concurrent use; a nil Recorder is
+// valid and records nothing.
+type Recorder interface {
+	// Request is called once per attempt with the host, the response
+	// status (0 when the transport errored), and the attempt duration.
+	Request(host string, status int, duration time.Duration)
+	// Retry is called each time an attempt is about to be re-run.
+	Retry(host string)
+	// RateLimitWait reports time spent blocked on the host limiter.
+	RateLimitWait(host string, wait time.Duration)
+	// BodyBytes reports bytes read from response bodies.
+	BodyBytes(host string, n int64)
+}
+
+// WithRecorder attaches a metrics recorder to this client.
+func WithRecorder(r Recorder) Option {
+	return func(c *Client) { c.recorder = r }
+}
+
+// defaultRecorder is consulted by New when no WithRecorder option is
+// given, so one SetDefaultRecorder call instruments every client built
+// afterwards — the same wiring style as slog's default logger.
+var defaultRecorder atomic.Value // Recorder
+
+// SetDefaultRecorder makes every subsequently built client report to r.
+func SetDefaultRecorder(r Recorder) {
+	defaultRecorder.Store(&r)
+}
+
+func getDefaultRecorder() Recorder {
+	if v, ok := defaultRecorder.Load().(*Recorder); ok {
+		return *v
+	}
+	return nil
+}
+
+// countingBody reports bytes read from a response body to the recorder.
+type countingBody struct {
+	io.ReadCloser
+	host string
+	rec  Recorder
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		b.rec.BodyBytes(b.host, int64(n))
+	}
+	return n, err
+}
+
+// instrumentBody wraps a response body so downloaded bytes are counted.
+func (c *Client) instrumentBody(req *http.Request, resp *http.Response) {
+	if c.recorder == nil || resp.Body == nil {
+		return
+	}
+	resp.Body = &countingBody{ReadCloser: resp.Body, host: req.URL.Host, rec: c.recorder}
+}
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// RoundTripperFunc adapts a function to http.RoundTripper, the usual
+// glue for inline middlewares and test fakes.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip implements http.RoundTripper.
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps a RoundTripper with extra behaviour — logging,
+// metrics, header injection, canned responses in tests — without
+// touching the client's own pipeline.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// Use appends a middleware to the chain. Middlewares run in the order
+// they were added, outside the client's built-in retry and header
+// handling.
+func (c *Client) Use(mw Middleware) {
+	c.middlewares = append(c.middlewares, mw)
+}
+
+// chain composes the full request pipeline: user middlewares (first
+// added outermost), then retry, then User-Agent injection, then the
+// single-attempt transport with its limiter, breaker, and cache.
+func (c *Client) chain() http.RoundTripper {
+	rt := http.RoundTripper(RoundTripperFunc(c.roundTripOnce))
+	rt = c.withUserAgent(rt)
+	rt = c.withRetry(rt)
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		rt = c.middlewares[i](rt)
+	}
+	return rt
+}
+
+// withUserAgent fills in the default User-Agent unless the caller (or
+// an outer middleware) already set one.
+func (c *Client) withUserAgent(next http.RoundTripper) http.RoundTripper {
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Header.Get("User-Agent") == "" {
+			req.Header.Set("User-Agent", c.userAgent)
+		}
+		return next.RoundTrip(req)
+	})
+}
+
+// withRetry re-attempts failed requests per the retry policy, honoring
+// Retry-After and slowing the host's limiter down on 429s.
+func (c *Client) withRetry(next http.RoundTripper) http.RoundTripper {
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		var resp *http.Response
+		var err error
+		for attempt := 0; ; attempt++ {
+			resp, err = next.RoundTrip(req)
+			if err == nil && !c.retry.Retryable(resp) {
+				return resp, nil
+			}
+			// An open circuit means retrying is exactly what we must not do.
+			if errors.Is(err, ErrCircuitOpen) {
+				return nil, err
+			}
+			if attempt >= c.retry.MaxRetries {
+				break
+			}
+			delay := c.retry.Backoff(attempt)
+			if resp != nil {
+				// Honor the server's Retry-After over our own backoff, and
+				// slow the shared limiter down when we're being throttled.
+				if ra, ok := RetryAfter(resp); ok && ra > delay {
+					delay = ra
+				}
+				if resp.StatusCode == http.StatusTooManyRequests {
+					c.host(req.URL.Host).noteRateLimited()
+				}
+				c.logger.Debug("retrying", "url", req.URL.String(), "status", resp.StatusCode, "attempt", attempt+1, "delay", delay)
+				resp.Body.Close()
+			}
+			// Backoff waits must die with the request's context, or a
+			// cancelled download keeps a goroutine sleeping.
+			timer := time.NewTimer(delay)
+			select {
+			case <-req.Context().Done():
+				timer.Stop()
+				return nil, req.Context().Err()
+			case <-timer.C:
+			}
+			if c.recorder != nil {
+				c.recorder.Retry(req.URL.Host)
+			}
+			// Requests with a body consumed it on the first attempt.
+			if req.GetBody != nil {
+				if body, bodyErr := req.GetBody(); bodyErr == nil {
+					req.Body = body
+				}
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("request to %s failed after %d attempts: %w", req.URL, c.retry.MaxRetries+1, err)
+		}
+		return resp, nil
+	})
+}
@@ -0,0 +1,48 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestUseComposesInOrder(t *testing.T) {
+	var order []string
+	tag := func(name string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				return next.RoundTrip(req)
+			})
+		}
+	}
+
+	c := New(WithRateLimit(1000, 1000))
+	c.Use(tag("outer"))
+	c.Use(tag("inner"))
+	// A canned-response fake terminates the chain before the network.
+	c.Use(func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewReader([]byte("fake"))),
+				Request:    req,
+			}, nil
+		})
+	})
+
+	resp, err := c.Get(context.Background(), "http://example.invalid/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "fake" {
+		t.Fatalf("body = %q, want fake", body)
+	}
+	if len(order) != 2 || order[0] != "outer" || order[1] != "inner" {
+		t.Fatalf("middleware order = %v, want [outer inner]", order)
+	}
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/net/http/httpproxy"
+	"golang.org/x/net/proxy"
+)
+
+// WithProxy routes requests through the given proxy URL. http://,
+// https://, and socks5:// schemes are supported, including userinfo for
+// proxies requiring basic auth. An empty URL is a no-op, so config
+// values can be passed through unconditionally.
+func WithProxy(rawURL string) Option {
+	return func(c *Client) {
+		if rawURL == "" {
+			return
+		}
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			c.setupErr = fmt.Errorf("invalid proxy URL %q: %w", rawURL, err)
+			return
+		}
+		switch u.Scheme {
+		case "socks5":
+			c.setupErr = c.useSOCKS5(u)
+		case "http", "https":
+			c.proxyURL = rawURL
+			c.applyHTTPProxy()
+		default:
+			c.setupErr = fmt.Errorf("unsupported proxy scheme %q", u.Scheme)
+		}
+	}
+}
+
+// WithNoProxy sets the comma-separated host list that bypasses the HTTP
+// proxy (same format as the NO_PROXY environment variable).
+func WithNoProxy(noProxy string) Option {
+	return func(c *Client) {
+		c.noProxy = noProxy
+		c.applyHTTPProxy()
+	}
+}
+
+// applyHTTPProxy installs the proxy function for an HTTP(S) proxy,
+// honoring the no-proxy list.
+func (c *Client) applyHTTPProxy() {
+	if c.proxyURL == "" {
+		return
+	}
+	cfg := &httpproxy.Config{
+		HTTPProxy:  c.proxyURL,
+		HTTPSProxy: c.proxyURL,
+		NoProxy:    c.noProxy,
+	}
+	fn := cfg.ProxyFunc()
+	c.transport().Proxy = func(req *http.Request) (*url.URL, error) {
+		return fn(req.URL)
+	}
+}
+
+// useSOCKS5 installs a SOCKS5 dialer on the transport.
+func (c *Client) useSOCKS5(u *url.URL) error {
+	var auth *proxy.Auth
+	if u.User != nil {
+		password, _ := u.User.Password()
+		auth = &proxy.Auth{User: u.User.Username(), Password: password}
+	}
+	dialer, err := proxy.SOCKS5("tcp", u.Host, auth, proxy.Direct)
+	if err != nil {
+		return fmt.Errorf("building SOCKS5 dialer: %w", err)
+	}
+	tr := c.transport()
+	tr.Proxy = nil
+	tr.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if cd, ok := dialer.(proxy.ContextDialer); ok {
+			return cd.DialContext(ctx, network, addr)
+		}
+		return dialer.Dial(network, addr)
+	}
+	return nil
+}
+
+// transport returns the client's *http.Transport, installing a default
+// one (which honors HTTPS_PROXY et al.) if needed.
+func (c *Client) transport() *http.Transport {
+	if tr, ok := c.httpClient.Transport.(*http.Transport); ok && tr != nil {
+		return tr
+	}
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	c.httpClient.Transport = tr
+	return tr
+}
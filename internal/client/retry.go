@@ -0,0 +1,61 @@
+package client
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried.
+type RetryPolicy struct {
+	// MaxRetries is the number of attempts after the first request.
+	MaxRetries int
+	// BaseDelay is the delay before the first retry; subsequent retries
+	// back off exponentially up to MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// DefaultRetryPolicy is used when no policy is supplied.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	BaseDelay:  500 * time.Millisecond,
+	MaxDelay:   10 * time.Second,
+}
+
+// Retryable reports whether a response status warrants a retry. Server
+// errors and 429 rate limiting are both retryable.
+func (p RetryPolicy) Retryable(resp *http.Response) bool {
+	return resp.StatusCode >= http.StatusInternalServerError ||
+		resp.StatusCode == http.StatusTooManyRequests
+}
+
+// RetryAfter parses the Retry-After header (delta-seconds or HTTP-date)
+// of a response, reporting whether one was present and usable.
+func RetryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if when, err := http.ParseTime(value); err == nil {
+		if d := time.Until(when); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// Backoff returns the delay to wait before the given retry attempt
+// (0-based), using exponential backoff capped at MaxDelay.
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	d := time.Duration(float64(p.BaseDelay) * math.Pow(2, float64(attempt)))
+	if d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	return d
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func respWithStatus(status int, headers map[string]string) *http.Response {
+	h := http.Header{}
+	for k, v := range headers {
+		h.Set(k, v)
+	}
+	return &http.Response{StatusCode: status, Header: h}
+}
+
+func TestRetryableStatuses(t *testing.T) {
+	p := DefaultRetryPolicy
+	cases := []struct {
+		status int
+		want   bool
+	}{
+		{http.StatusOK, false},
+		{http.StatusNotFound, false},
+		{http.StatusTooManyRequests, true},
+		{http.StatusInternalServerError, true},
+		{http.StatusBadGateway, true},
+	}
+	for _, c := range cases {
+		if got := p.Retryable(respWithStatus(c.status, nil)); got != c.want {
+			t.Errorf("Retryable(%d) = %v, want %v", c.status, got, c.want)
+		}
+	}
+}
+
+func TestRetryAfterSeconds(t *testing.T) {
+	resp := respWithStatus(429, map[string]string{"Retry-After": "7"})
+	d, ok := RetryAfter(resp)
+	if !ok || d != 7*time.Second {
+		t.Errorf("RetryAfter = %v, %v; want 7s, true", d, ok)
+	}
+}
+
+func TestRetryAfterHTTPDate(t *testing.T) {
+	when := time.Now().Add(30 * time.Second).UTC().Format(http.TimeFormat)
+	resp := respWithStatus(429, map[string]string{"Retry-After": when})
+	d, ok := RetryAfter(resp)
+	if !ok || d <= 0 || d > 31*time.Second {
+		t.Errorf("RetryAfter = %v, %v; want ~30s, true", d, ok)
+	}
+}
+
+func TestRetryAfterAbsent(t *testing.T) {
+	if _, ok := RetryAfter(respWithStatus(429, nil)); ok {
+		t.Error("RetryAfter reported a value for a response without the header")
+	}
+}
+
+func TestBackoffCapped(t *testing.T) {
+	p := RetryPolicy{MaxRetries: 10, BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+	if got := p.Backoff(0); got != time.Second {
+		t.Errorf("Backoff(0) = %v, want 1s", got)
+	}
+	if got := p.Backoff(8); got != 5*time.Second {
+		t.Errorf("Backoff(8) = %v, want capped 5s", got)
+	}
+}
+
+func TestRetryBackoffHonorsCancellation(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	c := New(
+		WithRateLimit(1000, 1000),
+		WithRetryPolicy(RetryPolicy{MaxRetries: 5, BaseDelay: time.Minute, MaxDelay: time.Minute}),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.Get(ctx, srv.URL, nil)
+		done <- err
+	}()
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("got %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("cancelled request still waiting out the backoff")
+	}
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"golang.org/x/time/rate"
+)
+
+// throttleChunk is the largest single read a throttled body serves, so
+// waits stay short and the rate stays smooth.
+const throttleChunk = 32 * 1024
+
+// ParseRate parses a human bandwidth figure like "500k" or "2m" into
+// bytes per second. Suffixes are binary (k=1024), matching curl's
+// --limit-rate. An empty string means unlimited and parses to 0.
+func ParseRate(s string) (int64, error) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	if s == "" {
+		return 0, nil
+	}
+	mult := int64(1)
+	switch {
+	case strings.HasSuffix(s, "g"):
+		mult, s = 1<<30, s[:len(s)-1]
+	case strings.HasSuffix(s, "m"):
+		mult, s = 1<<20, s[:len(s)-1]
+	case strings.HasSuffix(s, "k"):
+		mult, s = 1<<10, s[:len(s)-1]
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("bad rate %q (want e.g. 500k or 2m)", s)
+	}
+	return n * mult, nil
+}
+
+// throttledBody wraps a response body in a token-bucket limiter so
+// large downloads don't saturate the connection.
+type throttledBody struct {
+	ctx     context.Context
+	body    io.ReadCloser
+	limiter *rate.Limiter
+}
+
+func newThrottledBody(ctx context.Context, body io.ReadCloser, bytesPerSec int64) io.ReadCloser {
+	burst := throttleChunk
+	if int64(burst) > bytesPerSec {
+		burst = int(bytesPerSec)
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &throttledBody{
+		ctx:     ctx,
+		body:    body,
+		limiter: rate.NewLimiter(rate.Limit(bytesPerSec), burst),
+	}
+}
+
+func (t *throttledBody) Read(p []byte) (int, error) {
+	if len(p) > t.limiter.Burst() {
+		p = p[:t.limiter.Burst()]
+	}
+	n, err := t.body.Read(p)
+	if n > 0 {
+		if waitErr := t.limiter.WaitN(t.ctx, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
+
+func (t *throttledBody) Close() error {
+	return t.body.Close()
+}
@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestParseRate(t *testing.T) {
+	cases := map[string]int64{
+		"":     0,
+		"1024": 1024,
+		"500k": 500 * 1024,
+		"2m":   2 << 20,
+		"1g":   1 << 30,
+		"2M":   2 << 20,
+	}
+	for in, want := range cases {
+		got, err := ParseRate(in)
+		if err != nil {
+			t.Errorf("ParseRate(%q): %v", in, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("ParseRate(%q) = %d, want %d", in, got, want)
+		}
+	}
+	for _, bad := range []string{"fast", "-1k", "k"} {
+		if _, err := ParseRate(bad); err == nil {
+			t.Errorf("ParseRate(%q) should fail", bad)
+		}
+	}
+}
+
+func TestThrottledBodyReadsEverything(t *testing.T) {
+	payload := strings.Repeat("x", 200*1024)
+	body := newThrottledBody(context.Background(), io.NopCloser(strings.NewReader(payload)), 1<<30)
+	got, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if len(got) != len(payload) {
+		t.Errorf("read %d bytes, want %d", len(got), len(payload))
+	}
+}
@@ -0,0 +1,41 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// WithTLS tunes certificate verification for users behind
+// TLS-intercepting proxies: insecure disables verification entirely
+// (an explicit, logged escape hatch — never the default), and caFile
+// adds a custom CA bundle to the system roots.
+func WithTLS(insecure bool, caFile string) Option {
+	return func(c *Client) {
+		if !insecure && caFile == "" {
+			return
+		}
+		tlsCfg := &tls.Config{InsecureSkipVerify: insecure}
+		if insecure {
+			c.logger.Warn("TLS certificate verification disabled")
+		}
+		if caFile != "" {
+			pem, err := os.ReadFile(caFile)
+			if err != nil {
+				c.setupErr = fmt.Errorf("reading tls.ca_file: %w", err)
+				return
+			}
+			pool, err := x509.SystemCertPool()
+			if err != nil {
+				pool = x509.NewCertPool()
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				c.setupErr = fmt.Errorf("no certificates found in %s", caFile)
+				return
+			}
+			tlsCfg.RootCAs = pool
+		}
+		c.transport().TLSClientConfig = tlsCfg
+	}
+}
@@ -0,0 +1,370 @@
+// Package config loads and persists goreilly's configuration from
+// ~/.config/goreilly/config.yaml, with environment variable overrides.
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/viper"
+)
+
+// Config holds all user-tunable settings.
+type Config struct {
+	OutputDir         string  `mapstructure:"output_dir"`
+	UserAgent         string  `mapstructure:"user_agent"`
+	LogLevel          string  `mapstructure:"log_level"`
+	RequestsPerSecond float64 `mapstructure:"requests_per_second"`
+	Burst             int     `mapstructure:"burst"`
+	MaxRetries        int     `mapstructure:"max_retries"`
+
+	// RetryBaseDelay is the wait before the first retry; RetryMaxDelay
+	// caps the exponential backoff that follows.
+	RetryBaseDelay time.Duration `mapstructure:"retry_base_delay"`
+	RetryMaxDelay  time.Duration `mapstructure:"retry_max_delay"`
+
+	// CacheServerURL points at a teammate's goreilly cache serve
+	// instance; downloads try it before hitting O'Reilly.
+	CacheServerURL   string `mapstructure:"cache_server_url"`
+	CacheServerToken string `mapstructure:"cache_server_token"`
+
+	// LimitRate caps download bandwidth, e.g. "500k" or "2m"; empty
+	// means unlimited.
+	LimitRate string `mapstructure:"limit_rate"`
+
+	// MeteredSSIDs lists wifi networks (hotspots) on which downloads
+	// should pause automatically.
+	MeteredSSIDs []string `mapstructure:"metered_ssids"`
+
+	// SizeWarnBytes is the estimated batch size above which sync and
+	// other batch jobs warn before starting.
+	SizeWarnBytes int64 `mapstructure:"size_warn_bytes"`
+
+	// Changelog appends a CHANGELOG.md entry in the output directory on
+	// every library add, update, or removal.
+	Changelog bool `mapstructure:"changelog"`
+
+	// Proxy settings; HTTPS_PROXY-style env vars are honored when these
+	// are unset.
+	Proxy ProxyConfig `mapstructure:"proxy"`
+
+	// Reader holds terminal reading mode settings, separate from the
+	// main TUI theme.
+	Reader ReaderConfig `mapstructure:"reader"`
+
+	// Format holds locale preferences applied by listings everywhere.
+	Format FormatConfig `mapstructure:"format"`
+
+	// Delivery configures sending books to a Kindle address over SMTP.
+	Delivery DeliveryConfig `mapstructure:"delivery"`
+
+	// TLS tunes certificate verification for TLS-intercepting proxies.
+	TLS TLSConfig `mapstructure:"tls"`
+
+	// Readwise configures the highlight exporter.
+	Readwise ReadwiseConfig `mapstructure:"readwise"`
+
+	// Epub tunes EPUB assembly.
+	Epub EpubConfig `mapstructure:"epub"`
+}
+
+// EpubConfig tunes EPUB assembly.
+type EpubConfig struct {
+	// CodeFont is a font file (ttf, otf, woff, woff2) to embed for code
+	// listings; empty embeds nothing.
+	CodeFont string `mapstructure:"code_font"`
+	// SubsetFont cuts the embedded font down to the characters the book
+	// uses, when fonttools' pyftsubset is installed.
+	SubsetFont bool `mapstructure:"subset_font"`
+}
+
+// ReadwiseConfig holds the API token goreilly annotations readwise
+// authenticates with.
+type ReadwiseConfig struct {
+	Token string `mapstructure:"token"`
+}
+
+// TLSConfig overrides certificate verification. Verification is on by
+// default; these knobs exist for corporate middleboxes.
+type TLSConfig struct {
+	// Insecure disables certificate verification entirely.
+	Insecure bool `mapstructure:"insecure"`
+	// CAFile adds a PEM bundle to the trusted roots.
+	CAFile string `mapstructure:"ca_file"`
+}
+
+// DeliveryConfig holds the SMTP account and default target used by
+// goreilly send.
+type DeliveryConfig struct {
+	SMTPHost     string `mapstructure:"smtp_host"`
+	SMTPPort     int    `mapstructure:"smtp_port"`
+	SMTPUser     string `mapstructure:"smtp_user"`
+	SMTPPassword string `mapstructure:"smtp_password"`
+	From         string `mapstructure:"from"`
+	KindleEmail  string `mapstructure:"kindle_email"`
+
+	// SubjectTemplate and BodyTemplate are text/template strings with
+	// .Title, .Authors, .Chapters, and .Filename available.
+	SubjectTemplate string `mapstructure:"subject_template"`
+	BodyTemplate    string `mapstructure:"body_template"`
+	// ConvertKeyword prefixes the subject with Amazon's "Convert"
+	// keyword, asking the Kindle pipeline to reflow PDFs.
+	ConvertKeyword bool `mapstructure:"convert_keyword"`
+
+	// Devices maps a short name to a device profile for --send-to. The
+	// YAML shorthand `kindle: a@kindle.com` is equivalent to a profile
+	// with only the email set.
+	Devices map[string]DeviceConfig `mapstructure:"devices"`
+}
+
+// DeviceConfig describes one delivery target: where to send, which
+// format it reads best, and how large an attachment it accepts.
+type DeviceConfig struct {
+	Email string `mapstructure:"email"`
+	// Format is the preferred artifact, e.g. azw3 for a Kindle or kepub
+	// for a Kobo; empty infers a sensible order from the address.
+	Format string `mapstructure:"format"`
+	// MaxSizeBytes rejects attachments the device's mail gateway would
+	// bounce; zero means no limit.
+	MaxSizeBytes int64 `mapstructure:"max_size_bytes"`
+}
+
+// FormatConfig selects how dates and sizes are rendered.
+type FormatConfig struct {
+	Dates string `mapstructure:"dates"` // iso, us, or eu
+	Sizes string `mapstructure:"sizes"` // binary or decimal
+}
+
+// ProxyConfig configures outbound proxying.
+type ProxyConfig struct {
+	HTTP    string `mapstructure:"http"`
+	SOCKS5  string `mapstructure:"socks5"`
+	NoProxy string `mapstructure:"no_proxy"`
+}
+
+// ReaderConfig tunes the terminal reading mode for long sessions.
+type ReaderConfig struct {
+	Theme    string `mapstructure:"theme"` // dark, light, sepia, or high-contrast
+	MaxWidth int    `mapstructure:"max_width"`
+	Margin   int    `mapstructure:"margin"`
+}
+
+// envProfile selects the active profile when --profile is not given.
+const envProfile = "GOREILLY_PROFILE"
+
+// profile is the active named profile; empty means the default profile.
+var profile string
+
+// SetProfile selects a named profile. Config, token, catalog, and other
+// state files are scoped under profiles/<name>, so e.g. work and
+// personal accounts stay fully separate.
+func SetProfile(name string) {
+	profile = name
+}
+
+// Profile returns the active profile name, consulting GOREILLY_PROFILE
+// when none was set explicitly. Empty means the default profile.
+func Profile() string {
+	if profile != "" {
+		return profile
+	}
+	return os.Getenv(envProfile)
+}
+
+// Dir returns the goreilly configuration directory for the active
+// profile, following platform conventions (XDG on Linux,
+// os.UserConfigDir elsewhere).
+func Dir() string {
+	base := baseConfigDir()
+	if p := Profile(); p != "" {
+		return filepath.Join(base, "profiles", p)
+	}
+	return base
+}
+
+// DataDir returns where larger state (catalog, stats, bookmarks) lives
+// for the active profile, honoring XDG_DATA_HOME.
+func DataDir() string {
+	base := baseDataDir()
+	if p := Profile(); p != "" {
+		return filepath.Join(base, "profiles", p)
+	}
+	return base
+}
+
+// Path returns the configuration file path.
+func Path() string {
+	return filepath.Join(Dir(), "config.yaml")
+}
+
+// CookiesPath returns where the persistent cookie jar lives.
+func CookiesPath() string {
+	return filepath.Join(Dir(), "cookies.json")
+}
+
+// Manager reads and writes one configuration file through its own viper
+// instance, so concurrent managers (and tests) never share state.
+type Manager struct {
+	v    *viper.Viper
+	path string
+}
+
+// NewManager returns a Manager over the active profile's config file.
+func NewManager() *Manager {
+	return NewManagerAt(Path())
+}
+
+// NewManagerAt returns a Manager over an explicit config file path,
+// mainly for tests.
+func NewManagerAt(path string) *Manager {
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+	v.SetEnvPrefix("GOREILLY")
+	v.AutomaticEnv()
+	setDefaults(v)
+	return &Manager{v: v, path: path}
+}
+
+// Path returns the config file this Manager reads and writes.
+func (m *Manager) Path() string {
+	return m.path
+}
+
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("output_dir", "./books")
+	v.SetDefault("user_agent", "goreilly/1.0")
+	v.SetDefault("log_level", "info")
+	v.SetDefault("requests_per_second", 2.0)
+	v.SetDefault("burst", 4)
+	v.SetDefault("max_retries", 3)
+	v.SetDefault("retry_base_delay", "500ms")
+	v.SetDefault("retry_max_delay", "10s")
+	v.SetDefault("limit_rate", "")
+	v.SetDefault("cache_server_url", "")
+	v.SetDefault("cache_server_token", "")
+	v.SetDefault("metered_ssids", []string{})
+	v.SetDefault("size_warn_bytes", int64(1<<30))
+	v.SetDefault("changelog", false)
+	v.SetDefault("proxy.http", "")
+	v.SetDefault("proxy.socks5", "")
+	v.SetDefault("proxy.no_proxy", "")
+	v.SetDefault("reader.theme", "dark")
+	v.SetDefault("reader.max_width", 80)
+	v.SetDefault("reader.margin", 2)
+	v.SetDefault("format.dates", "iso")
+	v.SetDefault("format.sizes", "binary")
+	v.SetDefault("delivery.smtp_host", "")
+	v.SetDefault("delivery.smtp_port", 587)
+	v.SetDefault("delivery.smtp_user", "")
+	v.SetDefault("delivery.smtp_password", "")
+	v.SetDefault("delivery.from", "")
+	v.SetDefault("delivery.kindle_email", "")
+	v.SetDefault("delivery.subject_template", "{{.Title}}")
+	v.SetDefault("delivery.body_template", "{{.Title}}{{if .Authors}} by {{.Authors}}{{end}}, sent by goreilly.")
+	v.SetDefault("delivery.convert_keyword", false)
+	v.SetDefault("delivery.devices", map[string]string{})
+	v.SetDefault("tls.insecure", false)
+	v.SetDefault("tls.ca_file", "")
+	v.SetDefault("readwise.token", "")
+	v.SetDefault("epub.code_font", "")
+	v.SetDefault("epub.subset_font", true)
+}
+
+// Load reads the config file (if present) and environment overrides
+// (GOREILLY_*) into a Config.
+func (m *Manager) Load() (*Config, error) {
+	if err := m.v.ReadInConfig(); err != nil {
+		if _, ok := err.(*os.PathError); !ok {
+			if _, notFound := err.(viper.ConfigFileNotFoundError); !notFound {
+				return nil, fmt.Errorf("reading config: %w", err)
+			}
+		}
+	}
+	var cfg Config
+	if err := m.v.Unmarshal(&cfg, viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		deviceShorthandHook(),
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+	))); err != nil {
+		return nil, fmt.Errorf("parsing config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// deviceShorthandHook lets a device be written as just its address
+// (`kindle: a@kindle.com`) instead of a full profile map.
+func deviceShorthandHook() mapstructure.DecodeHookFuncType {
+	return func(from, to reflect.Type, data any) (any, error) {
+		if from.Kind() != reflect.String || to != reflect.TypeOf(DeviceConfig{}) {
+			return data, nil
+		}
+		return DeviceConfig{Email: data.(string)}, nil
+	}
+}
+
+// Save writes the configuration back to disk.
+func (m *Manager) Save(cfg *Config) error {
+	if err := os.MkdirAll(filepath.Dir(m.path), 0o755); err != nil {
+		return fmt.Errorf("creating config dir: %w", err)
+	}
+	m.v.Set("output_dir", cfg.OutputDir)
+	m.v.Set("user_agent", cfg.UserAgent)
+	m.v.Set("log_level", cfg.LogLevel)
+	m.v.Set("requests_per_second", cfg.RequestsPerSecond)
+	m.v.Set("burst", cfg.Burst)
+	m.v.Set("max_retries", cfg.MaxRetries)
+	m.v.Set("retry_base_delay", cfg.RetryBaseDelay.String())
+	m.v.Set("retry_max_delay", cfg.RetryMaxDelay.String())
+	m.v.Set("limit_rate", cfg.LimitRate)
+	m.v.Set("cache_server_url", cfg.CacheServerURL)
+	m.v.Set("cache_server_token", cfg.CacheServerToken)
+	m.v.Set("metered_ssids", cfg.MeteredSSIDs)
+	m.v.Set("size_warn_bytes", cfg.SizeWarnBytes)
+	m.v.Set("changelog", cfg.Changelog)
+	m.v.Set("proxy.http", cfg.Proxy.HTTP)
+	m.v.Set("proxy.socks5", cfg.Proxy.SOCKS5)
+	m.v.Set("proxy.no_proxy", cfg.Proxy.NoProxy)
+	m.v.Set("reader.theme", cfg.Reader.Theme)
+	m.v.Set("reader.max_width", cfg.Reader.MaxWidth)
+	m.v.Set("reader.margin", cfg.Reader.Margin)
+	m.v.Set("format.dates", cfg.Format.Dates)
+	m.v.Set("format.sizes", cfg.Format.Sizes)
+	m.v.Set("delivery.smtp_host", cfg.Delivery.SMTPHost)
+	m.v.Set("delivery.smtp_port", cfg.Delivery.SMTPPort)
+	m.v.Set("delivery.smtp_user", cfg.Delivery.SMTPUser)
+	m.v.Set("delivery.smtp_password", cfg.Delivery.SMTPPassword)
+	m.v.Set("delivery.from", cfg.Delivery.From)
+	m.v.Set("delivery.kindle_email", cfg.Delivery.KindleEmail)
+	m.v.Set("delivery.subject_template", cfg.Delivery.SubjectTemplate)
+	m.v.Set("delivery.body_template", cfg.Delivery.BodyTemplate)
+	m.v.Set("delivery.convert_keyword", cfg.Delivery.ConvertKeyword)
+	// Devices are written as plain maps so the YAML keys match the
+	// mapstructure tags on reload.
+	devices := make(map[string]map[string]any, len(cfg.Delivery.Devices))
+	for name, d := range cfg.Delivery.Devices {
+		devices[name] = map[string]any{"email": d.Email, "format": d.Format, "max_size_bytes": d.MaxSizeBytes}
+	}
+	m.v.Set("delivery.devices", devices)
+	m.v.Set("tls.insecure", cfg.TLS.Insecure)
+	m.v.Set("tls.ca_file", cfg.TLS.CAFile)
+	if err := m.v.WriteConfigAs(m.path); err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+	return nil
+}
+
+// Load reads the active profile's config through a fresh Manager. It is
+// a convenience for call sites that only need a one-shot read.
+func Load() (*Config, error) {
+	return NewManager().Load()
+}
+
+// Save writes cfg to the active profile's config file.
+func Save(cfg *Config) error {
+	return NewManager().Save(cfg)
+}
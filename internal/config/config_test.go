@@ -0,0 +1,68 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestManagerRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	m := NewManagerAt(path)
+
+	cfg, err := m.Load()
+	if err != nil {
+		t.Fatalf("Load with no file: %v", err)
+	}
+	cfg.OutputDir = "/tmp/books"
+	cfg.MaxRetries = 7
+	if err := m.Save(cfg); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	got, err := NewManagerAt(path).Load()
+	if err != nil {
+		t.Fatalf("Load after Save: %v", err)
+	}
+	if got.OutputDir != "/tmp/books" || got.MaxRetries != 7 {
+		t.Errorf("round trip lost values: %+v", got)
+	}
+}
+
+func TestManagerSetKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	m := NewManagerAt(path)
+	if err := m.SetKey("requests_per_second", "4.5"); err != nil {
+		t.Fatalf("SetKey: %v", err)
+	}
+	cfg, err := NewManagerAt(path).Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.RequestsPerSecond != 4.5 {
+		t.Errorf("requests_per_second = %v, want 4.5", cfg.RequestsPerSecond)
+	}
+
+	if err := m.SetKey("max_retries", "lots"); err == nil {
+		t.Error("SetKey accepted a non-integer for max_retries")
+	}
+	if err := m.SetKey("no_such_key", "x"); err == nil {
+		t.Error("SetKey accepted an unknown key")
+	}
+}
+
+func TestManagersAreIndependent(t *testing.T) {
+	dir := t.TempDir()
+	a := NewManagerAt(filepath.Join(dir, "a.yaml"))
+	b := NewManagerAt(filepath.Join(dir, "b.yaml"))
+
+	if err := a.SetKey("output_dir", "/a"); err != nil {
+		t.Fatalf("SetKey on a: %v", err)
+	}
+	cfgB, err := b.Load()
+	if err != nil {
+		t.Fatalf("Load b: %v", err)
+	}
+	if cfgB.OutputDir == "/a" {
+		t.Error("managers share state: b saw a's output_dir")
+	}
+}
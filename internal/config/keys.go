@@ -0,0 +1,180 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// keyKinds maps every settable config key to its value kind, used to
+// validate and parse values coming from the CLI.
+var keyKinds = map[string]string{
+	"output_dir":                "string",
+	"user_agent":                "string",
+	"log_level":                 "string",
+	"requests_per_second":       "float",
+	"burst":                     "int",
+	"max_retries":               "int",
+	"retry_base_delay":          "duration",
+	"retry_max_delay":           "duration",
+	"limit_rate":                "string",
+	"cache_server_url":          "string",
+	"cache_server_token":        "string",
+	"metered_ssids":             "list",
+	"size_warn_bytes":           "bytes",
+	"changelog":                 "bool",
+	"readwise.token":            "string",
+	"epub.code_font":            "string",
+	"epub.subset_font":          "bool",
+	"proxy.http":                "string",
+	"proxy.socks5":              "string",
+	"proxy.no_proxy":            "string",
+	"reader.theme":              "string",
+	"reader.max_width":          "int",
+	"reader.margin":             "int",
+	"format.dates":              "string",
+	"format.sizes":              "string",
+	"delivery.smtp_host":        "string",
+	"delivery.smtp_port":        "int",
+	"delivery.smtp_user":        "string",
+	"delivery.smtp_password":    "string",
+	"delivery.from":             "string",
+	"delivery.kindle_email":     "string",
+	"delivery.subject_template": "string",
+	"delivery.body_template":    "string",
+	"delivery.convert_keyword":  "bool",
+	"delivery.devices":          "map",
+	"tls.insecure":              "bool",
+	"tls.ca_file":               "string",
+}
+
+// Keys returns all settable config keys, sorted.
+func Keys() []string {
+	keys := make([]string, 0, len(keyKinds))
+	for k := range keyKinds {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ValidKey reports whether key names a known setting.
+func ValidKey(key string) bool {
+	_, ok := keyKinds[key]
+	return ok
+}
+
+// ParseValue parses a raw CLI string into the typed value for key.
+func ParseValue(key, raw string) (any, error) {
+	kind, ok := keyKinds[key]
+	if !ok {
+		return nil, fmt.Errorf("unknown config key %q (see goreilly config list)", key)
+	}
+	switch kind {
+	case "string":
+		return raw, nil
+	case "float":
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s wants a number, got %q", key, raw)
+		}
+		return v, nil
+	case "bool":
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s wants true or false, got %q", key, raw)
+		}
+		return v, nil
+	case "int":
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s wants an integer, got %q", key, raw)
+		}
+		return v, nil
+	case "duration":
+		v, err := time.ParseDuration(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s wants a duration like 500ms or 10s, got %q", key, raw)
+		}
+		// Stored as the canonical string; loading parses it back into a
+		// time.Duration.
+		return v.String(), nil
+	case "bytes":
+		v, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s wants a byte count, got %q", key, raw)
+		}
+		return v, nil
+	case "list":
+		if raw == "" {
+			return []string{}, nil
+		}
+		parts := strings.Split(raw, ",")
+		for i := range parts {
+			parts[i] = strings.TrimSpace(parts[i])
+		}
+		return parts, nil
+	case "map":
+		out := map[string]string{}
+		if raw == "" {
+			return out, nil
+		}
+		for _, pair := range strings.Split(raw, ",") {
+			name, value, ok := strings.Cut(pair, "=")
+			if !ok {
+				return nil, fmt.Errorf("%s wants name=value pairs, got %q", key, pair)
+			}
+			out[strings.TrimSpace(name)] = strings.TrimSpace(value)
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("unhandled kind %q for key %s", kind, key)
+	}
+}
+
+// Get returns the current value of key.
+func (m *Manager) Get(key string) (any, error) {
+	if !ValidKey(key) {
+		return nil, fmt.Errorf("unknown config key %q (see goreilly config list)", key)
+	}
+	if _, err := m.Load(); err != nil {
+		return nil, err
+	}
+	return m.v.Get(key), nil
+}
+
+// SetKey parses and persists a single key, leaving the rest of the file
+// as loaded.
+func (m *Manager) SetKey(key, raw string) error {
+	value, err := ParseValue(key, raw)
+	if err != nil {
+		return err
+	}
+	if _, err := m.Load(); err != nil {
+		return err
+	}
+	m.v.Set(key, value)
+	if err := os.MkdirAll(filepath.Dir(m.path), 0o755); err != nil {
+		return fmt.Errorf("creating config dir: %w", err)
+	}
+	if err := m.v.WriteConfigAs(m.path); err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+	return nil
+}
+
+// All returns every key with its current value.
+func (m *Manager) All() (map[string]any, error) {
+	if _, err := m.Load(); err != nil {
+		return nil, err
+	}
+	out := make(map[string]any, len(keyKinds))
+	for key := range keyKinds {
+		out[key] = m.v.Get(key)
+	}
+	return out, nil
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// configFiles and dataFiles are the known state files, used when
+// migrating a legacy ~/.config/goreilly directory to platform paths.
+var (
+	configFiles = []string{"config.yaml", "token.json", "cookies.json"}
+	dataFiles   = []string{"catalog.db", "events.jsonl", "bookmarks.json", "plan.json", "snippets.txt"}
+)
+
+// baseConfigDir resolves the platform config directory for goreilly,
+// honoring XDG_CONFIG_HOME and falling back to os.UserConfigDir (which
+// handles Windows and macOS conventions).
+func baseConfigDir() string {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "goreilly")
+	}
+	if dir, err := os.UserConfigDir(); err == nil {
+		return filepath.Join(dir, "goreilly")
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, ".config", "goreilly")
+	}
+	return ".goreilly"
+}
+
+// baseDataDir resolves the platform data directory, honoring
+// XDG_DATA_HOME on Linux; macOS and Windows keep data next to config.
+func baseDataDir() string {
+	if xdg := os.Getenv("XDG_DATA_HOME"); xdg != "" {
+		return filepath.Join(xdg, "goreilly")
+	}
+	if runtime.GOOS == "linux" {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, ".local", "share", "goreilly")
+		}
+	}
+	return baseConfigDir()
+}
+
+// legacyDir is where versions before the XDG support kept everything.
+func legacyDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "goreilly")
+}
+
+// MigrateLegacy moves files from the pre-XDG ~/.config/goreilly layout
+// into the current config and data directories. It is a no-op when the
+// layouts coincide or nothing is left to move, so it is safe to call on
+// every startup.
+func MigrateLegacy() error {
+	legacy := legacyDir()
+	if legacy == "" || Profile() != "" {
+		return nil
+	}
+	moves := map[string]string{}
+	if dir := Dir(); dir != legacy {
+		for _, name := range configFiles {
+			moves[name] = dir
+		}
+	}
+	if dir := DataDir(); dir != legacy {
+		for _, name := range dataFiles {
+			moves[name] = dir
+		}
+	}
+	for name, destDir := range moves {
+		src := filepath.Join(legacy, name)
+		dest := filepath.Join(destDir, name)
+		if _, err := os.Stat(src); err != nil {
+			continue
+		}
+		if _, err := os.Stat(dest); err == nil {
+			continue // never clobber files already in the new place
+		}
+		if err := os.MkdirAll(destDir, 0o755); err != nil {
+			return err
+		}
+		if err := os.Rename(src, dest); err != nil {
+			return err
+		}
+	}
+	return nil
+}
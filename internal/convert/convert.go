@@ -0,0 +1,111 @@
+// Package convert turns downloaded EPUBs into Kindle-friendly formats
+// by shelling out to Calibre's ebook-convert or Amazon's kindlegen,
+// whichever is installed.
+package convert
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Formats lists the conversion targets this package supports.
+var Formats = []string{"mobi", "azw3"}
+
+// Supported reports whether format is a known conversion target.
+func Supported(format string) bool {
+	for _, f := range Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// FromEPUB converts an EPUB to the given format and returns the output
+// path, written next to the input file.
+func FromEPUB(ctx context.Context, epubPath, format string) (string, error) {
+	if !Supported(format) {
+		return "", fmt.Errorf("unsupported conversion target %q (want one of %s)", format, strings.Join(Formats, ", "))
+	}
+	outPath := strings.TrimSuffix(epubPath, ".epub") + "." + format
+
+	if converter, err := exec.LookPath("ebook-convert"); err == nil {
+		return outPath, runConverter(ctx, converter, epubPath, outPath)
+	}
+	// kindlegen only produces MOBI and always writes next to the input.
+	if format == "mobi" {
+		if converter, err := exec.LookPath("kindlegen"); err == nil {
+			return outPath, runKindlegen(ctx, converter, epubPath, outPath)
+		}
+	}
+	return "", fmt.Errorf("%s output needs ebook-convert (Calibre) or kindlegen on PATH", format)
+}
+
+// PDFOptions tunes ebook-convert's PDF output for physical printing.
+// The zero value keeps Calibre's screen-oriented defaults.
+type PDFOptions struct {
+	// PageNumbers prints a page number at the bottom of every page.
+	PageNumbers bool
+	// ChapterHeaders puts the current section title in a running header.
+	ChapterHeaders bool
+	// WrapCode soft-wraps code listings instead of letting long lines
+	// run off the page, with continuation lines indented as a wrap
+	// marker.
+	WrapCode bool
+}
+
+// wrapCodeCSS soft-wraps <pre> blocks; the hanging indent makes wrapped
+// continuation lines visually distinct from real source lines.
+const wrapCodeCSS = `pre { white-space: pre-wrap; overflow-wrap: break-word; padding-left: 2em; text-indent: -2em; }`
+
+// args translates the options into ebook-convert flags.
+func (o PDFOptions) args() []string {
+	var out []string
+	if o.PageNumbers {
+		out = append(out, "--pdf-page-numbers")
+	}
+	if o.ChapterHeaders {
+		out = append(out, "--pdf-header-template", `<p style="text-align:left; font-size:smaller">_SECTION_</p>`)
+	}
+	if o.WrapCode {
+		out = append(out, "--extra-css", wrapCodeCSS)
+	}
+	return out
+}
+
+// ToPDF converts an EPUB to a PDF next to the input file, applying the
+// print options. Unlike the Kindle targets, PDF needs ebook-convert:
+// kindlegen has no PDF output.
+func ToPDF(ctx context.Context, epubPath string, opts PDFOptions) (string, error) {
+	converter, err := exec.LookPath("ebook-convert")
+	if err != nil {
+		return "", fmt.Errorf("pdf output needs Calibre's ebook-convert on PATH")
+	}
+	outPath := strings.TrimSuffix(epubPath, ".epub") + ".pdf"
+	return outPath, runConverter(ctx, converter, epubPath, outPath, opts.args()...)
+}
+
+func runConverter(ctx context.Context, converter, in, out string, extra ...string) error {
+	cmd := exec.CommandContext(ctx, converter, append([]string{in, out}, extra...)...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("ebook-convert failed: %v: %s", err, string(output))
+	}
+	return nil
+}
+
+func runKindlegen(ctx context.Context, converter, in, out string) error {
+	cmd := exec.CommandContext(ctx, converter, in, "-o", filepath.Base(out))
+	// kindlegen exits non-zero for warnings while still producing
+	// output, so only treat a missing output file as failure.
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if _, statErr := os.Stat(out); statErr != nil {
+			return fmt.Errorf("kindlegen failed: %v: %s", err, string(output))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+// Package delivery emails books to a Kindle (or any address) over SMTP
+// and keeps a receipt of every attempt in the catalog.
+package delivery
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/tuannvm/koreilly/internal/config"
+)
+
+// attachment media types by extension; Amazon's converter keys off
+// these.
+var mediaTypes = map[string]string{
+	".epub": "application/epub+zip",
+	".pdf":  "application/pdf",
+	".mobi": "application/x-mobipocket-ebook",
+	".azw3": "application/vnd.amazon.ebook",
+}
+
+// MessageData feeds the configured subject and body templates.
+type MessageData struct {
+	Title    string
+	Authors  string
+	Chapters int
+	Filename string
+}
+
+// renderMessage expands the configured templates for one delivery and
+// applies the Convert keyword toggle. Empty or broken templates fall
+// back to the bare filename so a typo in config never blocks a send.
+func renderMessage(cfg config.DeliveryConfig, data MessageData) (subject, body string) {
+	subject = renderTemplate(cfg.SubjectTemplate, data, data.Filename)
+	body = renderTemplate(cfg.BodyTemplate, data, "Sent by goreilly.")
+	if cfg.ConvertKeyword {
+		subject = "Convert " + subject
+	}
+	return subject, body
+}
+
+func renderTemplate(text string, data MessageData, fallback string) string {
+	if text == "" {
+		return fallback
+	}
+	tmpl, err := template.New("delivery").Parse(text)
+	if err != nil {
+		return fallback
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return fallback
+	}
+	if out := strings.TrimSpace(b.String()); out != "" {
+		return out
+	}
+	return fallback
+}
+
+// Send emails the file at path to target using the configured SMTP
+// account. The message is a minimal multipart mail with the book as a
+// base64 attachment, which is all the Kindle pipeline needs; data
+// fills the configured subject and body templates.
+func Send(cfg config.DeliveryConfig, path, target string, data MessageData) error {
+	if cfg.SMTPHost == "" {
+		return fmt.Errorf("delivery is not configured; set delivery.smtp_host and friends (see goreilly config list)")
+	}
+	from := cfg.From
+	if from == "" {
+		from = cfg.SMTPUser
+	}
+	if from == "" {
+		return fmt.Errorf("delivery.from or delivery.smtp_user must be set")
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if data.Filename == "" {
+		data.Filename = filepath.Base(path)
+	}
+	subject, body := renderMessage(cfg, data)
+	msg := buildMessage(from, target, filepath.Base(path), subject, body, content)
+
+	addr := fmt.Sprintf("%s:%d", cfg.SMTPHost, cfg.SMTPPort)
+	var auth smtp.Auth
+	if cfg.SMTPUser != "" {
+		auth = smtp.PlainAuth("", cfg.SMTPUser, cfg.SMTPPassword, cfg.SMTPHost)
+	}
+	if err := smtp.SendMail(addr, auth, from, []string{target}, msg); err != nil {
+		return fmt.Errorf("sending via %s: %w", addr, err)
+	}
+	return nil
+}
+
+// SendText emails a plain-text message through the configured SMTP
+// account — no attachment, used for watch-mode alerts.
+func SendText(cfg config.DeliveryConfig, to, subject, body string) error {
+	if cfg.SMTPHost == "" {
+		return fmt.Errorf("delivery is not configured; set delivery.smtp_host and friends (see goreilly config list)")
+	}
+	from := cfg.From
+	if from == "" {
+		from = cfg.SMTPUser
+	}
+	if from == "" {
+		return fmt.Errorf("delivery.from or delivery.smtp_user must be set")
+	}
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	msg.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(body)
+
+	addr := fmt.Sprintf("%s:%d", cfg.SMTPHost, cfg.SMTPPort)
+	var auth smtp.Auth
+	if cfg.SMTPUser != "" {
+		auth = smtp.PlainAuth("", cfg.SMTPUser, cfg.SMTPPassword, cfg.SMTPHost)
+	}
+	if err := smtp.SendMail(addr, auth, from, []string{to}, []byte(msg.String())); err != nil {
+		return fmt.Errorf("sending via %s: %w", addr, err)
+	}
+	return nil
+}
+
+// buildMessage assembles the RFC 2045 multipart message with the book
+// attached.
+func buildMessage(from, to, filename, subject, body string, content []byte) []byte {
+	const boundary = "goreilly-attachment"
+	mediaType := mediaTypes[filepath.Ext(filename)]
+	if mediaType == "" {
+		mediaType = "application/octet-stream"
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&b, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&b, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", boundary)
+
+	fmt.Fprintf(&b, "--%s\r\n", boundary)
+	fmt.Fprintf(&b, "Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	fmt.Fprintf(&b, "%s\r\n\r\n", body)
+
+	fmt.Fprintf(&b, "--%s\r\n", boundary)
+	fmt.Fprintf(&b, "Content-Type: %s\r\n", mediaType)
+	fmt.Fprintf(&b, "Content-Transfer-Encoding: base64\r\n")
+	fmt.Fprintf(&b, "Content-Disposition: attachment; filename=%s\r\n\r\n", mime.QEncoding.Encode("utf-8", filename))
+
+	encoded := base64.StdEncoding.EncodeToString(content)
+	// Base64 lines are capped at 76 characters per RFC 2045.
+	for len(encoded) > 0 {
+		n := 76
+		if n > len(encoded) {
+			n = len(encoded)
+		}
+		b.WriteString(encoded[:n])
+		b.WriteString("\r\n")
+		encoded = encoded[n:]
+	}
+	fmt.Fprintf(&b, "--%s--\r\n", boundary)
+	return b.Bytes()
+}
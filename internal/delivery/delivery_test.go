@@ -0,0 +1,44 @@
+package delivery
+
+import (
+	"testing"
+
+	"github.com/tuannvm/koreilly/internal/config"
+)
+
+func TestRenderMessage(t *testing.T) {
+	data := MessageData{Title: "Learning Go", Authors: "Jon Bodner", Chapters: 16, Filename: "learning-go.epub"}
+
+	cfg := config.DeliveryConfig{
+		SubjectTemplate: "{{.Title}}",
+		BodyTemplate:    "{{.Title}} by {{.Authors}} ({{.Chapters}} chapters)",
+	}
+	subject, body := renderMessage(cfg, data)
+	if subject != "Learning Go" {
+		t.Errorf("subject = %q", subject)
+	}
+	if body != "Learning Go by Jon Bodner (16 chapters)" {
+		t.Errorf("body = %q", body)
+	}
+
+	cfg.ConvertKeyword = true
+	subject, _ = renderMessage(cfg, data)
+	if subject != "Convert Learning Go" {
+		t.Errorf("subject with convert keyword = %q", subject)
+	}
+}
+
+func TestRenderMessageFallsBack(t *testing.T) {
+	data := MessageData{Filename: "book.epub"}
+	cfg := config.DeliveryConfig{
+		SubjectTemplate: "{{.Broken",
+		BodyTemplate:    "",
+	}
+	subject, body := renderMessage(cfg, data)
+	if subject != "book.epub" {
+		t.Errorf("broken template subject = %q, want the filename", subject)
+	}
+	if body != "Sent by goreilly." {
+		t.Errorf("empty template body = %q", body)
+	}
+}
@@ -0,0 +1,105 @@
+package delivery
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tuannvm/koreilly/internal/catalog"
+	"github.com/tuannvm/koreilly/internal/config"
+)
+
+// DataFor fills template fields for a delivery, pulling title and
+// authors from the catalog when the book is known there.
+func DataFor(cat *catalog.Catalog, bookID, path string) MessageData {
+	data := MessageData{Filename: filepath.Base(path)}
+	if bookID == "" {
+		return data
+	}
+	for _, format := range []string{"epub", "mobi", "azw3", "pdf"} {
+		r, err := cat.Get(bookID, format)
+		if err != nil || r == nil {
+			continue
+		}
+		data.Title = r.Title
+		data.Authors = strings.Join(r.Authors, ", ")
+		break
+	}
+	return data
+}
+
+// Retry backoff: attempts wait retryBase doubling each time, capped at
+// retryMax, so a mail server outage isn't hammered.
+const (
+	retryBase = time.Minute
+	retryMax  = time.Hour
+)
+
+// backoff returns the wait before the next attempt after n failures.
+func backoff(n int) time.Duration {
+	d := retryBase
+	for i := 1; i < n && d < retryMax; i++ {
+		d *= 2
+	}
+	if d > retryMax {
+		d = retryMax
+	}
+	return d
+}
+
+// Enqueue records a failed delivery for a later retry; a repeat failure
+// of the same file and target updates the existing entry's backoff.
+func Enqueue(cat *catalog.Catalog, p catalog.PendingDelivery, cause error) error {
+	p.Attempts++
+	p.LastError = cause.Error()
+	p.NextAttempt = time.Now().Add(backoff(p.Attempts))
+	return cat.PendingPut(p)
+}
+
+// RetryResult summarizes one ProcessPending run.
+type RetryResult struct {
+	Sent     int
+	Deferred int
+	Failed   int
+}
+
+// ProcessPending attempts queued deliveries. Entries still inside their
+// backoff window are skipped unless force is set; a success is receipted
+// and removed, a failure pushes the next attempt further out.
+func ProcessPending(cfg config.DeliveryConfig, cat *catalog.Catalog, force bool) (RetryResult, error) {
+	var res RetryResult
+	pending, err := cat.PendingList()
+	if err != nil {
+		return res, err
+	}
+	for _, p := range pending {
+		if !force && time.Now().Before(p.NextAttempt) {
+			res.Deferred++
+			continue
+		}
+		sendErr := Send(cfg, p.Path, p.Target, DataFor(cat, p.BookID, p.Path))
+		receipt := catalog.Delivery{
+			BookID: p.BookID,
+			Path:   p.Path,
+			SHA256: p.SHA256,
+			Target: p.Target,
+			OK:     sendErr == nil,
+		}
+		if sendErr != nil {
+			receipt.Error = sendErr.Error()
+			res.Failed++
+			if err := Enqueue(cat, p, sendErr); err != nil {
+				return res, err
+			}
+		} else {
+			res.Sent++
+			if err := cat.PendingDelete(p.Path, p.Target); err != nil {
+				return res, err
+			}
+		}
+		if err := cat.DeliveryAppend(receipt); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,78 @@
+// Package dict looks up word definitions locally, via the dict(1)
+// client when installed or a DICT protocol (RFC 2229) server on
+// localhost, so the reader never has to leave the terminal.
+package dict
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// serverAddr is the conventional local dictd address.
+const serverAddr = "127.0.0.1:2628"
+
+// Lookup returns the definition of word from the first local source
+// that answers: the dict binary, then a dictd server on localhost.
+func Lookup(ctx context.Context, word string) (string, error) {
+	word = strings.TrimSpace(word)
+	if word == "" {
+		return "", fmt.Errorf("no word to look up")
+	}
+	if path, err := exec.LookPath("dict"); err == nil {
+		out, err := exec.CommandContext(ctx, path, word).Output()
+		if err == nil && len(out) > 0 {
+			return string(out), nil
+		}
+	}
+	return lookupDICT(ctx, word)
+}
+
+// lookupDICT speaks just enough of the DICT protocol to fetch the
+// first definition of word from all databases.
+func lookupDICT(ctx context.Context, word string) (string, error) {
+	dialer := net.Dialer{Timeout: 3 * time.Second}
+	conn, err := dialer.DialContext(ctx, "tcp", serverAddr)
+	if err != nil {
+		return "", fmt.Errorf("no local dictionary: install dict(1) or run dictd (%v)", err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	r := bufio.NewReader(conn)
+	if _, err := r.ReadString('\n'); err != nil { // 220 greeting
+		return "", err
+	}
+	if _, err := fmt.Fprintf(conn, "DEFINE ! %q\r\n", word); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	inBody := false
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case strings.HasPrefix(line, "552"):
+			return "", fmt.Errorf("no definition found for %q", word)
+		case strings.HasPrefix(line, "5"), strings.HasPrefix(line, "4"):
+			return "", fmt.Errorf("dict server error: %s", line)
+		case strings.HasPrefix(line, "151"):
+			inBody = true
+		case strings.HasPrefix(line, "250"):
+			return b.String(), nil
+		case line == "." && inBody:
+			inBody = false
+			b.WriteString("\n")
+		case inBody:
+			b.WriteString(strings.TrimPrefix(line, ".") + "\n")
+		}
+	}
+}
@@ -0,0 +1,297 @@
+// Package downloader assembles books into local files. It holds the
+// download logic shared by the CLI commands and the TUI, which each
+// bring their own way of reporting progress.
+package downloader
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/tuannvm/koreilly/internal/cacheserver"
+	"github.com/tuannvm/koreilly/internal/catalog"
+	"github.com/tuannvm/koreilly/internal/config"
+	"github.com/tuannvm/koreilly/internal/epub"
+	"github.com/tuannvm/koreilly/internal/logging"
+	"github.com/tuannvm/koreilly/internal/manifest"
+	"github.com/tuannvm/koreilly/internal/quirks"
+	"github.com/tuannvm/koreilly/internal/sanitize"
+	"github.com/tuannvm/koreilly/internal/services/oreilly"
+	"github.com/tuannvm/koreilly/internal/stats"
+	"github.com/tuannvm/koreilly/internal/util"
+)
+
+// Progress receives human-readable status lines during a download; nil
+// silences them.
+type Progress func(format string, args ...any)
+
+func (p Progress) printf(format string, args ...any) {
+	if p != nil {
+		p(format, args...)
+	}
+}
+
+// FetchBook fetches a book's metadata and chapters once, ready for
+// assembly into any output. A non-nil selection restricts the fetch to
+// the named chapters; nil fetches the whole book.
+func FetchBook(ctx context.Context, svc *oreilly.Service, jwt, bookID string, sel *ChapterSelection, report Progress) (*oreilly.Book, epub.Metadata, []epub.Chapter, error) {
+	var meta epub.Metadata
+	book, err := svc.FetchBookDetail(ctx, jwt, bookID)
+	if err != nil {
+		return nil, meta, nil, err
+	}
+	toc, err := svc.FetchTOC(ctx, jwt, bookID)
+	if err != nil {
+		return nil, meta, nil, err
+	}
+	flat := oreilly.FlattenChapters(toc)
+
+	// Whole-book fetches spool chapters to disk as they arrive, so an
+	// interrupted run resumes instead of re-fetching; partial selections
+	// are cheap enough to not bother.
+	var sp *spool
+	if sel == nil {
+		sp = openSpool(bookID)
+	}
+
+	var chapters []epub.Chapter
+	seen := make(map[string]bool)
+	// Learning paths stitch the same material under different hrefs;
+	// hashing the content catches those repeats too.
+	seenContent := make(map[[sha256.Size]byte]string)
+	num := 0
+	for _, ch := range flat {
+		if ch.Href == "" || seen[ch.Href] {
+			continue
+		}
+		seen[ch.Href] = true
+		num++
+		if !sel.Contains(num) {
+			continue
+		}
+		content, spooled := sp.get(ch.Href)
+		if spooled {
+			report.printf("Resuming %s from a previous run", ch.Title)
+		} else {
+			report.printf("Fetching %s...", ch.Title)
+			content, err = svc.FetchChapterContent(ctx, jwt, bookID, ch.Href)
+			if err != nil {
+				return nil, meta, nil, err
+			}
+			content = sanitize.Chapter(content)
+			if len(book.Publishers) > 0 {
+				content = quirks.Apply(book.Publishers[0], content)
+			}
+			content = flattenChapterLinks(content)
+			sp.put(ch.Href, content)
+		}
+		sum := sha256.Sum256(content)
+		if prev, dup := seenContent[sum]; dup {
+			report.printf("Skipping %s: identical content to %s", ch.Title, prev)
+			continue
+		}
+		seenContent[sum] = ch.Title
+		chapters = append(chapters, epub.Chapter{
+			Title:    ch.Title,
+			Filename: filepath.Base(ch.Href),
+			Content:  content,
+		})
+	}
+	if sel != nil {
+		if len(chapters) == 0 {
+			return nil, meta, nil, fmt.Errorf("no chapters matched %q (book has %d)", sel, num)
+		}
+		if sel.Max() > num {
+			report.printf("Note: book has only %d chapters; spec %q reaches past the end", num, sel)
+		}
+	}
+
+	// Whole books get a back-of-book index even when the publisher
+	// didn't ship one; partial selections stay as picked.
+	if sel == nil && len(chapters) > 0 && !hasIndexChapter(chapters) {
+		chapters = append(chapters, buildTermIndex(chapters))
+	}
+
+	meta = epub.Metadata{
+		Identifier:  book.ISBN,
+		Title:       book.Title,
+		Authors:     book.Authors,
+		Description: book.Description,
+	}
+	if meta.Identifier == "" {
+		meta.Identifier = "urn:orm:book:" + bookID
+	}
+	if len(book.Publishers) > 0 {
+		meta.Publisher = book.Publishers[0]
+	}
+	return book, meta, chapters, nil
+}
+
+// DedupPolicy controls how Book treats a title that already exists in
+// the catalog or on disk.
+type DedupPolicy int
+
+const (
+	// DedupDefault skips when the catalog record is in place and its
+	// recorded checksum still matches the file on disk.
+	DedupDefault DedupPolicy = iota
+	// DedupSkipExisting additionally skips when the destination file
+	// exists at all, catalogued or not.
+	DedupSkipExisting
+	// DedupForce always re-downloads, overwriting whatever is there.
+	DedupForce
+)
+
+// Book assembles a book into an EPUB under outDir, recording it in the
+// catalog and stats, and returns the written path and chapter count. A
+// book already in the catalog (or available from the team cache) is not
+// re-downloaded.
+func Book(ctx context.Context, cfg *config.Config, svc *oreilly.Service, jwt, bookID, outDir string, report Progress) (string, int, error) {
+	path, chapters, _, err := BookWith(ctx, cfg, svc, jwt, bookID, outDir, report, DedupDefault)
+	return path, chapters, err
+}
+
+// BookWith is Book with an explicit dedup policy; the boolean reports
+// that an existing copy was kept, so batch runs can count skips.
+func BookWith(ctx context.Context, cfg *config.Config, svc *oreilly.Service, jwt, bookID, outDir string, report Progress, policy DedupPolicy) (string, int, bool, error) {
+	log := logging.ForBook(bookID)
+	// Skip books the catalog already knows about, as long as the file
+	// is still in place and its checksum still matches what was
+	// recorded; a changed file is treated as missing and re-downloaded.
+	if existing, err := catalogGet(bookID, "epub"); policy != DedupForce && err == nil && existing != nil {
+		if _, statErr := os.Stat(existing.Path); statErr == nil {
+			if sum, sumErr := manifest.FileSHA256(existing.Path); sumErr == nil && existing.SHA256 != "" && sum != existing.SHA256 {
+				report.printf("Checksum mismatch on %s, re-downloading", existing.Path)
+			} else {
+				log.Debug("already in catalog", "path", existing.Path)
+				report.printf("Already downloaded: %s (%s)", existing.Title, existing.Path)
+				return existing.Path, 0, true, nil
+			}
+		}
+	}
+	// --skip-existing also trusts a bare file at the destination; the
+	// metadata request needed to name it is cheap and cached.
+	if policy == DedupSkipExisting {
+		if detail, err := svc.FetchBookDetail(ctx, jwt, bookID); err == nil {
+			existingPath := filepath.Join(outDir, util.SanitizeFilename(detail.Title)+".epub")
+			if _, statErr := os.Stat(existingPath); statErr == nil {
+				report.printf("Already on disk: %s", existingPath)
+				return existingPath, 0, true, nil
+			}
+		}
+	}
+
+	// A teammate's cache may already have the book; --force wants a
+	// fresh copy from the source instead.
+	if cfg != nil && cfg.CacheServerURL != "" && policy != DedupForce {
+		if err := os.MkdirAll(outDir, 0o755); err != nil {
+			return "", 0, false, fmt.Errorf("creating output dir: %w", err)
+		}
+		destPath := filepath.Join(outDir, util.SanitizeFilename(bookID)+".epub")
+		ok, err := cacheserver.Fetch(ctx, cfg.CacheServerURL, cfg.CacheServerToken, bookID, "epub", destPath)
+		if err != nil {
+			report.printf("Peer cache unavailable (%v), downloading directly", err)
+		} else if ok {
+			sum, _ := manifest.FileSHA256(destPath)
+			if err := catalogPut(catalog.Record{
+				ID: bookID, Title: bookID, Path: destPath, Format: "epub", SHA256: sum,
+			}); err != nil {
+				return destPath, 0, false, fmt.Errorf("recording cached download: %w", err)
+			}
+			report.printf("Fetched %s from team cache", bookID)
+			AppendChangelog(cfg, fmt.Sprintf("added %s from team cache (epub)", bookID))
+			return destPath, 0, false, nil
+		}
+	}
+
+	log.Debug("download starting", "out_dir", outDir)
+	book, meta, chapters, err := FetchBook(ctx, svc, jwt, bookID, nil, report)
+	if err != nil {
+		log.Warn("download failed", "err", err)
+		return "", 0, false, err
+	}
+
+	if cfg != nil && cfg.Epub.CodeFont != "" {
+		meta.CodeFont, err = epub.LoadCodeFont(cfg.Epub.CodeFont, cfg.Epub.SubsetFont, chapters)
+		if err != nil {
+			return "", 0, false, fmt.Errorf("embedding code font: %w", err)
+		}
+	}
+
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return "", 0, false, fmt.Errorf("creating output dir: %w", err)
+	}
+	outPath := filepath.Join(outDir, util.SanitizeFilename(book.Title)+".epub")
+	_, statErr := os.Stat(outPath)
+	updated := statErr == nil
+	f, err := os.Create(outPath)
+	if err != nil {
+		return "", 0, false, err
+	}
+	defer f.Close()
+
+	if err := epub.Build(f, meta, chapters); err != nil {
+		return "", 0, false, err
+	}
+	// The book made it to disk whole; the spooled chapters have served
+	// their purpose.
+	ClearSpool(bookID)
+	info, _ := f.Stat()
+	var size int64
+	if info != nil {
+		size = info.Size()
+	}
+	_ = stats.NewStore().Append(stats.Event{
+		Type:   stats.EventDownload,
+		BookID: bookID,
+		Title:  book.Title,
+		Bytes:  size,
+	})
+
+	sum, err := manifest.FileSHA256(outPath)
+	if err != nil {
+		sum = ""
+	}
+	if err := catalogPut(catalog.Record{
+		ID:      bookID,
+		Title:   book.Title,
+		Authors: book.Authors,
+		Topics:  book.Topics,
+		ISBN:    book.ISBN,
+		Path:    outPath,
+		Format:  "epub",
+		SHA256:  sum,
+	}); err != nil {
+		return outPath, len(chapters), false, fmt.Errorf("recording download in catalog: %w", err)
+	}
+	action := "added"
+	if updated {
+		action = "updated"
+	}
+	AppendChangelog(cfg, fmt.Sprintf("%s %s (epub, %d chapters)", action, book.Title, len(chapters)))
+	log.Debug("download complete", "path", outPath, "chapters", len(chapters))
+	return outPath, len(chapters), false, nil
+}
+
+// catalogGet and catalogPut open the catalog only for the duration of
+// one read or write, so concurrent queue workers never contend for the
+// database lock across a whole download.
+func catalogGet(id, format string) (*catalog.Record, error) {
+	cat, err := catalog.Open(catalog.DefaultPath())
+	if err != nil {
+		return nil, err
+	}
+	defer cat.Close()
+	return cat.Get(id, format)
+}
+
+func catalogPut(r catalog.Record) error {
+	cat, err := catalog.Open(catalog.DefaultPath())
+	if err != nil {
+		return err
+	}
+	defer cat.Close()
+	return cat.Put(r)
+}
@@ -0,0 +1,110 @@
+package downloader
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/tuannvm/koreilly/internal/client"
+	"github.com/tuannvm/koreilly/internal/services/oreilly"
+)
+
+// calibreOPFTemplate is the Calibre-style OPF 2.0 sidecar; Calibre reads
+// this shape when importing, preserving authors, ISBN, tags, and the
+// description that an EPUB-embedded package can lose in conversion.
+var calibreOPFTemplate = template.Must(template.New("calibre-opf").Funcs(template.FuncMap{
+	"esc": xmlEscape,
+}).Parse(`<?xml version='1.0' encoding='utf-8'?>
+<package xmlns="http://www.idpf.org/2007/opf" unique-identifier="bookid" version="2.0">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:opf="http://www.idpf.org/2007/opf">
+    <dc:title>{{esc .Title}}</dc:title>
+    {{- range .Authors}}
+    <dc:creator opf:role="aut">{{esc .}}</dc:creator>
+    {{- end}}
+    {{- if .ISBN}}
+    <dc:identifier opf:scheme="ISBN" id="bookid">{{esc .ISBN}}</dc:identifier>
+    {{- end}}
+    {{- range .Publishers}}
+    <dc:publisher>{{esc .}}</dc:publisher>
+    {{- end}}
+    {{- if .Issued}}
+    <dc:date>{{esc .Issued}}</dc:date>
+    {{- end}}
+    {{- range .Topics}}
+    <dc:subject>{{esc .}}</dc:subject>
+    {{- end}}
+    {{- if .Description}}
+    <dc:description>{{esc .Description}}</dc:description>
+    {{- end}}
+    <dc:language>en</dc:language>
+  </metadata>
+</package>
+`))
+
+func xmlEscape(s string) string {
+	r := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;", "'", "&apos;")
+	return r.Replace(s)
+}
+
+// WriteCalibre writes a Calibre metadata.opf sidecar (and the cover
+// image, when the platform has one) next to an assembled book, so a
+// Calibre import keeps authors, ISBN, tags, and description. Because
+// books share a flat output directory, the sidecars are named after the
+// book like the README is. The returned paths are everything written.
+func WriteCalibre(ctx context.Context, svc *oreilly.Service, jwt, bookID, bookPath string) ([]string, error) {
+	book, err := svc.FetchBookDetail(ctx, jwt, bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	var opf strings.Builder
+	if err := calibreOPFTemplate.Execute(&opf, book); err != nil {
+		return nil, fmt.Errorf("rendering metadata.opf: %w", err)
+	}
+	base := strings.TrimSuffix(bookPath, ".epub")
+	opfPath := base + ".metadata.opf"
+	if err := os.WriteFile(opfPath, []byte(opf.String()), 0o644); err != nil {
+		return nil, err
+	}
+	paths := []string{opfPath}
+
+	// The cover is best-effort: a missing or broken image shouldn't fail
+	// the download, and Calibre imports fine without one.
+	if book.CoverURL != "" {
+		coverPath, err := fetchCoverImage(ctx, book.CoverURL, base)
+		if err == nil {
+			paths = append(paths, coverPath)
+		}
+	}
+	return paths, nil
+}
+
+// fetchCoverImage downloads the cover next to the book, keeping the
+// extension the URL carries (Calibre accepts jpg and png alike).
+func fetchCoverImage(ctx context.Context, url, base string) (string, error) {
+	resp, err := client.New().Get(ctx, url, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("cover returned status %d", resp.StatusCode)
+	}
+	ext := ".jpg"
+	if strings.HasSuffix(strings.ToLower(url), ".png") {
+		ext = ".png"
+	}
+	coverPath := base + ".cover" + ext
+	f, err := os.Create(coverPath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		return "", err
+	}
+	return coverPath, nil
+}
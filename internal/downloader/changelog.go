@@ -0,0 +1,47 @@
+package downloader
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tuannvm/koreilly/internal/config"
+)
+
+// changelogHeader starts a fresh CHANGELOG.md so readers syncing the
+// directory elsewhere know what the file is.
+const changelogHeader = `# Library changelog
+
+Maintained by goreilly; one line per library change, newest last.
+
+`
+
+// AppendChangelog appends one dated entry to CHANGELOG.md in the output
+// directory when the changelog config option is on. Writing the entry
+// is best-effort: the library change itself has already happened, so a
+// failure here is only logged.
+func AppendChangelog(cfg *config.Config, entry string) {
+	if cfg == nil || !cfg.Changelog || cfg.OutputDir == "" {
+		return
+	}
+	path := filepath.Join(cfg.OutputDir, "CHANGELOG.md")
+	line := fmt.Sprintf("- %s — %s\n", time.Now().Format("2006-01-02 15:04"), entry)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		line = changelogHeader + line
+	}
+	if err := os.MkdirAll(cfg.OutputDir, 0o755); err != nil {
+		slog.Warn("changelog not written", "err", err)
+		return
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		slog.Warn("changelog not written", "err", err)
+		return
+	}
+	defer f.Close()
+	if _, err := f.WriteString(line); err != nil {
+		slog.Warn("changelog not written", "err", err)
+	}
+}
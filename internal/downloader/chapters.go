@@ -0,0 +1,89 @@
+package downloader
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ChapterSelection names a subset of a book's chapters by one-based
+// position, as counted in reading order. A nil selection means every
+// chapter.
+type ChapterSelection struct {
+	ranges [][2]int
+}
+
+// ParseChapterSpec parses a spec like "1,4-6" into a selection.
+func ParseChapterSpec(spec string) (*ChapterSelection, error) {
+	sel := &ChapterSelection{}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		lo, hi := part, part
+		if i := strings.Index(part, "-"); i >= 0 {
+			lo, hi = strings.TrimSpace(part[:i]), strings.TrimSpace(part[i+1:])
+		}
+		start, err := strconv.Atoi(lo)
+		if err != nil || start < 1 {
+			return nil, fmt.Errorf("bad chapter range %q in %q", part, spec)
+		}
+		end, err := strconv.Atoi(hi)
+		if err != nil || end < start {
+			return nil, fmt.Errorf("bad chapter range %q in %q", part, spec)
+		}
+		sel.ranges = append(sel.ranges, [2]int{start, end})
+	}
+	if len(sel.ranges) == 0 {
+		return nil, fmt.Errorf("empty chapter spec %q", spec)
+	}
+	sort.Slice(sel.ranges, func(i, j int) bool { return sel.ranges[i][0] < sel.ranges[j][0] })
+	return sel, nil
+}
+
+// Contains reports whether the one-based chapter number n is selected.
+// A nil selection contains everything.
+func (s *ChapterSelection) Contains(n int) bool {
+	if s == nil {
+		return true
+	}
+	for _, r := range s.ranges {
+		if n >= r[0] && n <= r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// Max returns the highest selected chapter number, used to spot specs
+// that reach past the end of a book.
+func (s *ChapterSelection) Max() int {
+	if s == nil || len(s.ranges) == 0 {
+		return 0
+	}
+	max := 0
+	for _, r := range s.ranges {
+		if r[1] > max {
+			max = r[1]
+		}
+	}
+	return max
+}
+
+// String renders the selection back in "1,4-6" form.
+func (s *ChapterSelection) String() string {
+	if s == nil {
+		return ""
+	}
+	parts := make([]string, 0, len(s.ranges))
+	for _, r := range s.ranges {
+		if r[0] == r[1] {
+			parts = append(parts, strconv.Itoa(r[0]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", r[0], r[1]))
+		}
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,41 @@
+package downloader
+
+import "testing"
+
+func TestParseChapterSpec(t *testing.T) {
+	sel, err := ParseChapterSpec("1, 4-6")
+	if err != nil {
+		t.Fatalf("ParseChapterSpec: %v", err)
+	}
+	for _, n := range []int{1, 4, 5, 6} {
+		if !sel.Contains(n) {
+			t.Errorf("chapter %d should be selected", n)
+		}
+	}
+	for _, n := range []int{2, 3, 7} {
+		if sel.Contains(n) {
+			t.Errorf("chapter %d should not be selected", n)
+		}
+	}
+	if got := sel.String(); got != "1,4-6" {
+		t.Errorf("String() = %q, want %q", got, "1,4-6")
+	}
+	if got := sel.Max(); got != 6 {
+		t.Errorf("Max() = %d, want 6", got)
+	}
+}
+
+func TestParseChapterSpecRejectsBadInput(t *testing.T) {
+	for _, spec := range []string{"", "0", "a", "3-1", "1-", "-2"} {
+		if _, err := ParseChapterSpec(spec); err == nil {
+			t.Errorf("ParseChapterSpec(%q) should fail", spec)
+		}
+	}
+}
+
+func TestNilSelectionContainsEverything(t *testing.T) {
+	var sel *ChapterSelection
+	if !sel.Contains(1) || !sel.Contains(999) {
+		t.Error("nil selection should contain every chapter")
+	}
+}
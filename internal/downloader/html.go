@@ -0,0 +1,68 @@
+package downloader
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tuannvm/koreilly/internal/epub"
+	"github.com/tuannvm/koreilly/internal/services/oreilly"
+	"github.com/tuannvm/koreilly/internal/util"
+)
+
+// htmlStyle keeps the export readable without any external assets.
+const htmlStyle = `body{max-width:46em;margin:2em auto;padding:0 1em;font-family:Georgia,serif;line-height:1.5}
+nav{font-family:sans-serif;font-size:.9em;margin-bottom:2em}
+pre{overflow-x:auto;background:#f4f4f4;padding:1em}`
+
+// WriteHTML exports a fetched book as a self-contained static
+// directory: an index page plus one HTML file per chapter with
+// prev/next navigation, readable in any browser without an EPUB
+// reader. It returns the directory written.
+func WriteHTML(book *oreilly.Book, chapters []epub.Chapter, outDir string) (string, error) {
+	dir := filepath.Join(outDir, util.SanitizeFilename(book.Title)+"-html")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	names := make([]string, len(chapters))
+	for i, ch := range chapters {
+		names[i] = fmt.Sprintf("%03d-%s", i+1, strings.TrimSuffix(ch.Filename, filepath.Ext(ch.Filename))+".html")
+	}
+
+	var index strings.Builder
+	fmt.Fprintf(&index, "<!DOCTYPE html>\n<html><head><meta charset=\"utf-8\"><title>%s</title><style>%s</style></head><body>\n", book.Title, htmlStyle)
+	fmt.Fprintf(&index, "<h1>%s</h1>\n", book.Title)
+	if len(book.Authors) > 0 {
+		fmt.Fprintf(&index, "<p>By %s</p>\n", strings.Join(book.Authors, ", "))
+	}
+	index.WriteString("<ol>\n")
+	for i, ch := range chapters {
+		fmt.Fprintf(&index, "<li><a href=%q>%s</a></li>\n", names[i], ch.Title)
+	}
+	index.WriteString("</ol>\n</body></html>\n")
+	if err := os.WriteFile(filepath.Join(dir, "index.html"), []byte(index.String()), 0o644); err != nil {
+		return "", err
+	}
+
+	for i, ch := range chapters {
+		var b strings.Builder
+		fmt.Fprintf(&b, "<!DOCTYPE html>\n<html><head><meta charset=\"utf-8\"><title>%s</title><style>%s</style></head><body>\n", ch.Title, htmlStyle)
+		b.WriteString("<nav>")
+		if i > 0 {
+			fmt.Fprintf(&b, "<a href=%q>&larr; prev</a> | ", names[i-1])
+		}
+		b.WriteString(`<a href="index.html">contents</a>`)
+		if i < len(chapters)-1 {
+			fmt.Fprintf(&b, " | <a href=%q>next &rarr;</a>", names[i+1])
+		}
+		b.WriteString("</nav>\n")
+		b.Write(ch.Content)
+		b.WriteString("\n</body></html>\n")
+		if err := os.WriteFile(filepath.Join(dir, names[i]), []byte(b.String()), 0o644); err != nil {
+			return "", err
+		}
+	}
+	return dir, nil
+}
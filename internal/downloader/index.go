@@ -0,0 +1,130 @@
+package downloader
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/tuannvm/koreilly/internal/epub"
+)
+
+// maxIndexTerms caps the generated index so a jargon-heavy book doesn't
+// produce an index longer than its chapters.
+const maxIndexTerms = 300
+
+// flattenChapterLinks rewrites relative links between chapters to their
+// base file name, preserving fragments. Chapters are assembled into a
+// flat directory, so publisher hrefs like text/ch01.html#sec1 — which
+// back-of-book index pages are full of — would otherwise dangle.
+func flattenChapterLinks(content []byte) []byte {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(content))
+	if err != nil {
+		return content
+	}
+	changed := false
+	doc.Find("a").Each(func(_ int, s *goquery.Selection) {
+		href, ok := s.Attr("href")
+		if !ok || href == "" || strings.Contains(href, "://") ||
+			strings.HasPrefix(href, "#") || strings.HasPrefix(href, "mailto:") {
+			return
+		}
+		if i := strings.LastIndex(href, "/"); i >= 0 {
+			s.SetAttr("href", href[i+1:])
+			changed = true
+		}
+	})
+	if !changed {
+		return content
+	}
+	out, err := doc.Html()
+	if err != nil {
+		return content
+	}
+	return []byte(out)
+}
+
+// hasIndexChapter reports whether the TOC already carries a publisher
+// back-of-book index.
+func hasIndexChapter(chapters []epub.Chapter) bool {
+	for _, ch := range chapters {
+		if strings.EqualFold(strings.TrimSpace(ch.Title), "index") {
+			return true
+		}
+	}
+	return false
+}
+
+// buildTermIndex generates a simple back-of-book index chapter when the
+// publisher didn't ship one, by collecting emphasized terms and linking
+// each to the chapters it appears in. It is no substitute for an
+// editorial index, but it makes a reference book navigable.
+func buildTermIndex(chapters []epub.Chapter) epub.Chapter {
+	terms := map[string]map[int]bool{}
+	for i, ch := range chapters {
+		doc, err := goquery.NewDocumentFromReader(bytes.NewReader(ch.Content))
+		if err != nil {
+			continue
+		}
+		doc.Find("em, strong, dfn").Each(func(_ int, s *goquery.Selection) {
+			term := strings.TrimSpace(s.Text())
+			if !indexableTerm(term) {
+				return
+			}
+			if terms[term] == nil {
+				terms[term] = map[int]bool{}
+			}
+			terms[term][i] = true
+		})
+	}
+
+	sorted := make([]string, 0, len(terms))
+	for term := range terms {
+		sorted = append(sorted, term)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return strings.ToLower(sorted[i]) < strings.ToLower(sorted[j])
+	})
+	if len(sorted) > maxIndexTerms {
+		sorted = sorted[:maxIndexTerms]
+	}
+
+	var b strings.Builder
+	b.WriteString(`<html><head><title>Index</title></head><body><h1>Index</h1>`)
+	b.WriteString(`<p>Generated from emphasized terms; the publisher shipped no index.</p>`)
+	for _, term := range sorted {
+		idxs := make([]int, 0, len(terms[term]))
+		for i := range terms[term] {
+			idxs = append(idxs, i)
+		}
+		sort.Ints(idxs)
+		var links []string
+		for _, i := range idxs {
+			links = append(links, fmt.Sprintf(`<a href="%s">%d</a>`, chapters[i].Filename, i+1))
+		}
+		fmt.Fprintf(&b, `<p>%s — %s</p>`, xmlEscape(term), strings.Join(links, ", "))
+	}
+	b.WriteString(`</body></html>`)
+
+	return epub.Chapter{
+		Title:    "Index",
+		Filename: "goreilly-index.xhtml",
+		Content:  []byte(b.String()),
+	}
+}
+
+// indexableTerm filters emphasis runs down to plausible index entries:
+// short noun-ish phrases rather than whole emphasized sentences.
+func indexableTerm(term string) bool {
+	if len(term) < 3 || len(term) > 40 {
+		return false
+	}
+	if len(strings.Fields(term)) > 3 {
+		return false
+	}
+	r := []rune(term)[0]
+	return unicode.IsLetter(r)
+}
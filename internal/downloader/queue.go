@@ -0,0 +1,194 @@
+package downloader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/tuannvm/koreilly/internal/catalog"
+	"github.com/tuannvm/koreilly/internal/config"
+	"github.com/tuannvm/koreilly/internal/services/oreilly"
+)
+
+// Queue manages the persistent download queue stored in the catalog
+// database. Items survive restarts; Process drains whatever is queued
+// with bounded concurrency. Pause and Remove take effect immediately
+// for items this process is running, and before the next claim for
+// everything else.
+type Queue struct {
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+}
+
+// NewQueue returns a queue handle. State lives in the catalog DB, so
+// handles are cheap and need no cleanup.
+func NewQueue() *Queue {
+	return &Queue{cancels: make(map[string]context.CancelFunc)}
+}
+
+// withCatalog runs fn against a briefly-opened catalog, keeping the
+// exclusive database lock short-lived.
+func withCatalog(fn func(*catalog.Catalog) error) error {
+	cat, err := catalog.Open(catalog.DefaultPath())
+	if err != nil {
+		return err
+	}
+	defer cat.Close()
+	return fn(cat)
+}
+
+// Add enqueues a book. Re-adding a failed, done, or paused item resets
+// it to queued; a running item is left alone.
+func (q *Queue) Add(id, title string) error {
+	return withCatalog(func(cat *catalog.Catalog) error {
+		if existing, err := cat.QueueGet(id); err != nil {
+			return err
+		} else if existing != nil && existing.Status == catalog.QueueRunning {
+			return nil
+		}
+		return cat.QueuePut(catalog.QueueItem{ID: id, Title: title, Status: catalog.QueueQueued})
+	})
+}
+
+// List returns every queue item.
+func (q *Queue) List() ([]catalog.QueueItem, error) {
+	var items []catalog.QueueItem
+	err := withCatalog(func(cat *catalog.Catalog) error {
+		var err error
+		items, err = cat.QueueList()
+		return err
+	})
+	return items, err
+}
+
+// Remove deletes an item, cancelling it first if this process is
+// downloading it.
+func (q *Queue) Remove(id string) error {
+	q.cancel(id)
+	return withCatalog(func(cat *catalog.Catalog) error {
+		return cat.QueueDelete(id)
+	})
+}
+
+// Pause marks an item paused and interrupts it if it is running here.
+func (q *Queue) Pause(id string) error {
+	q.cancel(id)
+	return q.setStatus(id, catalog.QueuePaused, "")
+}
+
+// Resume puts a paused or failed item back in line.
+func (q *Queue) Resume(id string) error {
+	return q.setStatus(id, catalog.QueueQueued, "")
+}
+
+func (q *Queue) cancel(id string) {
+	q.mu.Lock()
+	if cancel := q.cancels[id]; cancel != nil {
+		cancel()
+	}
+	q.mu.Unlock()
+}
+
+func (q *Queue) get(id string) (*catalog.QueueItem, error) {
+	var item *catalog.QueueItem
+	err := withCatalog(func(cat *catalog.Catalog) error {
+		var err error
+		item, err = cat.QueueGet(id)
+		return err
+	})
+	return item, err
+}
+
+func (q *Queue) setStatus(id, status, errText string) error {
+	return withCatalog(func(cat *catalog.Catalog) error {
+		item, err := cat.QueueGet(id)
+		if err != nil {
+			return err
+		}
+		if item == nil {
+			return fmt.Errorf("no queued item %s", id)
+		}
+		item.Status = status
+		item.Error = errText
+		return cat.QueuePut(*item)
+	})
+}
+
+// Process drains queued items with the given concurrency, downloading
+// each into outDir. It returns once nothing claimable is left; paused
+// and failed items stay behind for a later run.
+func (q *Queue) Process(ctx context.Context, cfg *config.Config, svc *oreilly.Service, jwt, outDir string, concurrency int, report Progress) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	items, err := q.List()
+	if err != nil {
+		return err
+	}
+	work := make(chan string, len(items))
+	for _, item := range items {
+		if item.Status == catalog.QueueQueued {
+			work <- item.ID
+		}
+	}
+	close(work)
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range work {
+				q.runOne(ctx, cfg, svc, jwt, id, outDir, report)
+			}
+		}()
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+// runOne claims a single item and downloads it, recording the outcome.
+func (q *Queue) runOne(ctx context.Context, cfg *config.Config, svc *oreilly.Service, jwt, id, outDir string, report Progress) {
+	// Re-check the status at claim time: another process (or a pause)
+	// may have gotten here first.
+	claimed := false
+	err := withCatalog(func(cat *catalog.Catalog) error {
+		item, err := cat.QueueGet(id)
+		if err != nil || item == nil || item.Status != catalog.QueueQueued {
+			return err
+		}
+		item.Status = catalog.QueueRunning
+		item.Error = ""
+		claimed = true
+		return cat.QueuePut(*item)
+	})
+	if err != nil || !claimed {
+		return
+	}
+
+	itemCtx, cancel := context.WithCancel(ctx)
+	q.mu.Lock()
+	q.cancels[id] = cancel
+	q.mu.Unlock()
+	defer func() {
+		cancel()
+		q.mu.Lock()
+		delete(q.cancels, id)
+		q.mu.Unlock()
+	}()
+
+	_, _, err = Book(itemCtx, cfg, svc, jwt, id, outDir, report)
+	switch {
+	case err == nil:
+		_ = q.setStatus(id, catalog.QueueDone, "")
+	case errors.Is(itemCtx.Err(), context.Canceled):
+		// Pause or Remove already recorded the item's fate; put an
+		// interrupted run (ctrl-c) back in line otherwise.
+		if item, getErr := q.get(id); getErr == nil && item != nil && item.Status == catalog.QueueRunning {
+			_ = q.setStatus(id, catalog.QueueQueued, "")
+		}
+	default:
+		_ = q.setStatus(id, catalog.QueueFailed, err.Error())
+	}
+}
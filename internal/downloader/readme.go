@@ -0,0 +1,84 @@
+package downloader
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/tuannvm/koreilly/internal/services/oreilly"
+)
+
+// githubRepo spots a companion repository link inside the publisher's
+// description, which is where O'Reilly books usually mention one.
+var githubRepo = regexp.MustCompile(`https://github\.com/[\w.-]+/[\w.-]+`)
+
+// WriteReadme writes a Markdown summary next to an assembled book:
+// metadata, the table of contents, any companion repository, and the
+// command that refreshes the file. Because books share a flat output
+// directory, the file is named after the book rather than README.md.
+func WriteReadme(ctx context.Context, svc *oreilly.Service, jwt, bookID, bookPath string) (string, error) {
+	book, err := svc.FetchBookDetail(ctx, jwt, bookID)
+	if err != nil {
+		return "", err
+	}
+	toc, err := svc.FetchTOC(ctx, jwt, bookID)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", book.Title)
+	if len(book.Authors) > 0 {
+		fmt.Fprintf(&b, "By %s.\n\n", strings.Join(book.Authors, ", "))
+	}
+	if len(book.Publishers) > 0 {
+		fmt.Fprintf(&b, "- Publisher: %s\n", book.Publishers[0])
+	}
+	if book.Issued != "" {
+		fmt.Fprintf(&b, "- Published: %s\n", book.Issued)
+	}
+	if book.ISBN != "" {
+		fmt.Fprintf(&b, "- ISBN: %s\n", book.ISBN)
+	}
+	if len(book.Topics) > 0 {
+		fmt.Fprintf(&b, "- Topics: %s\n", strings.Join(book.Topics, ", "))
+	}
+	if book.WebURL != "" {
+		fmt.Fprintf(&b, "- Platform page: %s\n", book.WebURL)
+	}
+	if repo := githubRepo.FindString(book.Description); repo != "" {
+		fmt.Fprintf(&b, "- Companion repository: %s\n", repo)
+	}
+	b.WriteString("\n")
+	if desc := descriptionText(book.Description); desc != "" {
+		fmt.Fprintf(&b, "%s\n\n", desc)
+	}
+
+	b.WriteString("## Contents\n\n")
+	for i, ch := range oreilly.FlattenChapters(toc) {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, ch.Title)
+	}
+	b.WriteString("\n## Updating\n\n")
+	fmt.Fprintf(&b, "Regenerate this file and re-download the book with:\n\n")
+	fmt.Fprintf(&b, "    goreilly download %s --readme\n", bookID)
+
+	readmePath := strings.TrimSuffix(bookPath, ".epub") + ".README.md"
+	if err := os.WriteFile(readmePath, []byte(b.String()), 0o644); err != nil {
+		return "", err
+	}
+	return readmePath, nil
+}
+
+// descriptionText flattens the HTML description the API returns into
+// plain text for the summary paragraph.
+func descriptionText(html string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return strings.TrimSpace(html)
+	}
+	return strings.TrimSpace(doc.Text())
+}
@@ -0,0 +1,82 @@
+package downloader
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/tuannvm/koreilly/internal/catalog"
+	"github.com/tuannvm/koreilly/internal/config"
+	"github.com/tuannvm/koreilly/internal/util"
+)
+
+// spool keeps the sanitized chapters of an in-flight download on disk,
+// with a matching catalog record, so a run interrupted by a crash or
+// ctrl-c resumes from what was already fetched instead of discarding
+// it. Spooling is strictly best-effort: any filesystem trouble just
+// means chapters are re-fetched.
+type spool struct {
+	bookID string
+	dir    string
+	count  int
+}
+
+// openSpool returns the spool for a book, creating its directory. A nil
+// spool is valid and does nothing, mirroring ChapterSelection.
+func openSpool(bookID string) *spool {
+	dir := filepath.Join(config.DataDir(), "partial", util.SanitizeFilename(bookID))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil
+	}
+	s := &spool{bookID: bookID, dir: dir}
+	if entries, err := os.ReadDir(dir); err == nil {
+		s.count = len(entries)
+	}
+	return s
+}
+
+func (s *spool) path(href string) string {
+	return filepath.Join(s.dir, util.SanitizeFilename(filepath.Base(href)))
+}
+
+// get returns a previously spooled chapter.
+func (s *spool) get(href string) ([]byte, bool) {
+	if s == nil {
+		return nil, false
+	}
+	data, err := os.ReadFile(s.path(href))
+	return data, err == nil
+}
+
+// put spools a fetched chapter and records the state in the catalog.
+func (s *spool) put(href string, content []byte) {
+	if s == nil {
+		return
+	}
+	if err := os.WriteFile(s.path(href), content, 0o644); err != nil {
+		return
+	}
+	s.count++
+	_ = withCatalog(func(cat *catalog.Catalog) error {
+		return cat.PartialPut(catalog.Partial{ID: s.bookID, Dir: s.dir, Chapters: s.count})
+	})
+}
+
+// clear discards the spool once the book is fully assembled.
+func (s *spool) clear() {
+	if s == nil {
+		return
+	}
+	_ = os.RemoveAll(s.dir)
+	_ = withCatalog(func(cat *catalog.Catalog) error {
+		return cat.PartialDelete(s.bookID)
+	})
+}
+
+// ClearSpool discards any spooled state for a book; callers that
+// assemble outside the catalog path use it after a successful build.
+func ClearSpool(bookID string) {
+	(&spool{
+		bookID: bookID,
+		dir:    filepath.Join(config.DataDir(), "partial", util.SanitizeFilename(bookID)),
+	}).clear()
+}
@@ -0,0 +1,162 @@
+// Package epub assembles EPUB 3 files from downloaded chapter content.
+package epub
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Metadata describes the book being assembled.
+type Metadata struct {
+	Identifier  string
+	Title       string
+	Authors     []string
+	Publisher   string
+	Description string
+	Language    string
+
+	// CodeFont, when set, is embedded and applied to code listings via
+	// a stylesheet linked from every chapter.
+	CodeFont *CodeFont
+}
+
+// Chapter is one content document of the assembled EPUB, in spine order.
+type Chapter struct {
+	Title    string
+	Filename string // file name inside OEBPS/, e.g. ch01.xhtml
+	Content  []byte // XHTML body
+}
+
+const containerXML = `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>`
+
+var opfTemplate = template.Must(template.New("opf").Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="bookid">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:identifier id="bookid">{{.Meta.Identifier}}</dc:identifier>
+    <dc:title>{{.Meta.Title}}</dc:title>
+    {{- range .Meta.Authors}}
+    <dc:creator>{{.}}</dc:creator>
+    {{- end}}
+    {{- if .Meta.Publisher}}
+    <dc:publisher>{{.Meta.Publisher}}</dc:publisher>
+    {{- end}}
+    <dc:language>{{.Meta.Language}}</dc:language>
+    <meta property="dcterms:modified">{{.Modified}}</meta>
+  </metadata>
+  <manifest>
+    <item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+    {{- with .Meta.CodeFont}}
+    <item id="code-css" href="code.css" media-type="text/css"/>
+    <item id="code-font" href="fonts/{{.Filename}}" media-type="{{.MediaType}}"/>
+    {{- end}}
+    {{- range $i, $c := .Chapters}}
+    <item id="ch{{$i}}" href="{{$c.Filename}}" media-type="application/xhtml+xml"/>
+    {{- end}}
+  </manifest>
+  <spine>
+    {{- range $i, $c := .Chapters}}
+    <itemref idref="ch{{$i}}"/>
+    {{- end}}
+  </spine>
+</package>`))
+
+var navTemplate = template.Must(template.New("nav").Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops">
+<head><title>{{.Meta.Title}}</title></head>
+<body>
+<nav epub:type="toc">
+<h1>Table of Contents</h1>
+<ol>
+{{- range .Chapters}}
+  <li><a href="{{.Filename}}">{{.Title}}</a></li>
+{{- end}}
+</ol>
+</nav>
+</body>
+</html>`))
+
+// Build writes a complete EPUB to w.
+func Build(w io.Writer, meta Metadata, chapters []Chapter) error {
+	if meta.Language == "" {
+		meta.Language = "en"
+	}
+	zw := zip.NewWriter(w)
+
+	// The mimetype entry must come first and be stored uncompressed.
+	mt, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return err
+	}
+	if _, err := mt.Write([]byte("application/epub+zip")); err != nil {
+		return err
+	}
+
+	if err := writeFile(zw, "META-INF/container.xml", []byte(containerXML)); err != nil {
+		return err
+	}
+
+	data := struct {
+		Meta     Metadata
+		Chapters []Chapter
+		Modified string
+	}{meta, chapters, time.Now().UTC().Format("2006-01-02T15:04:05Z")}
+
+	var opf, nav strings.Builder
+	if err := opfTemplate.Execute(&opf, data); err != nil {
+		return fmt.Errorf("rendering OPF: %w", err)
+	}
+	if err := navTemplate.Execute(&nav, data); err != nil {
+		return fmt.Errorf("rendering nav: %w", err)
+	}
+	if err := writeFile(zw, "OEBPS/content.opf", []byte(opf.String())); err != nil {
+		return err
+	}
+	if err := writeFile(zw, "OEBPS/nav.xhtml", []byte(nav.String())); err != nil {
+		return err
+	}
+	if meta.CodeFont != nil {
+		if err := writeFile(zw, "OEBPS/code.css", meta.CodeFont.css()); err != nil {
+			return err
+		}
+		if err := writeFile(zw, "OEBPS/fonts/"+meta.CodeFont.Filename, meta.CodeFont.Data); err != nil {
+			return err
+		}
+	}
+	for _, ch := range chapters {
+		content := ch.Content
+		if meta.CodeFont != nil {
+			content = linkStylesheet(content, "code.css")
+		}
+		if err := writeFile(zw, "OEBPS/"+ch.Filename, content); err != nil {
+			return fmt.Errorf("writing chapter %s: %w", ch.Filename, err)
+		}
+	}
+	return zw.Close()
+}
+
+// linkStylesheet references href from a chapter document by inserting a
+// link element at the end of its head. Documents without a head (which
+// sanitization normally guarantees) are left untouched.
+func linkStylesheet(content []byte, href string) []byte {
+	link := fmt.Sprintf(`<link rel="stylesheet" type="text/css" href=%q/></head>`, href)
+	out := strings.Replace(string(content), "</head>", link, 1)
+	return []byte(out)
+}
+
+func writeFile(zw *zip.Writer, name string, content []byte) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(content)
+	return err
+}
@@ -0,0 +1,112 @@
+package epub
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// CodeFont is a font embedded into the assembled EPUB for code
+// listings, so devices without a decent built-in monospace still render
+// code readably.
+type CodeFont struct {
+	Filename string // file name under OEBPS/fonts/
+	Data     []byte
+}
+
+// fontMediaTypes maps font file extensions to their manifest media
+// types.
+var fontMediaTypes = map[string]string{
+	".ttf":   "font/ttf",
+	".otf":   "font/otf",
+	".woff":  "font/woff",
+	".woff2": "font/woff2",
+}
+
+// MediaType returns the manifest media type for the font file.
+func (f *CodeFont) MediaType() string {
+	if mt := fontMediaTypes[strings.ToLower(filepath.Ext(f.Filename))]; mt != "" {
+		return mt
+	}
+	return "application/octet-stream"
+}
+
+// codeFontCSS styles code listings with the embedded face, falling back
+// to the device monospace when the font lacks a glyph.
+const codeFontCSS = `@font-face {
+  font-family: "goreilly-code";
+  src: url(fonts/%s);
+}
+pre, code, tt, kbd, samp {
+  font-family: "goreilly-code", monospace;
+}
+`
+
+// css renders the stylesheet referencing the embedded font.
+func (f *CodeFont) css() []byte {
+	return []byte(fmt.Sprintf(codeFontCSS, f.Filename))
+}
+
+// LoadCodeFont reads the font at path for embedding. When subset is
+// true and fonttools' pyftsubset is installed, the font is cut down to
+// the characters the chapters actually use, which typically shrinks it
+// by an order of magnitude; without pyftsubset the full font embeds.
+func LoadCodeFont(path string, subset bool, chapters []Chapter) (*CodeFont, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading code font: %w", err)
+	}
+	if _, ok := fontMediaTypes[strings.ToLower(filepath.Ext(path))]; !ok {
+		return nil, fmt.Errorf("unsupported font format %q (want ttf, otf, woff, or woff2)", filepath.Ext(path))
+	}
+	if subset {
+		if sub, err := subsetFont(path, chapters); err == nil {
+			data = sub
+		}
+	}
+	return &CodeFont{Filename: filepath.Base(path), Data: data}, nil
+}
+
+// subsetFont shells out to pyftsubset with the set of characters found
+// in the chapters. Any failure just means embedding the full font, so
+// callers treat errors as a fallback signal rather than fatal.
+func subsetFont(path string, chapters []Chapter) ([]byte, error) {
+	tool, err := exec.LookPath("pyftsubset")
+	if err != nil {
+		return nil, err
+	}
+	dir, err := os.MkdirTemp("", "goreilly-subset-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	textPath := filepath.Join(dir, "chars.txt")
+	if err := os.WriteFile(textPath, []byte(usedRunes(chapters)), 0o600); err != nil {
+		return nil, err
+	}
+	outPath := filepath.Join(dir, "subset"+filepath.Ext(path))
+	cmd := exec.Command(tool, path, "--text-file="+textPath, "--output-file="+outPath)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("pyftsubset failed: %v: %s", err, out)
+	}
+	return os.ReadFile(outPath)
+}
+
+// usedRunes returns every distinct character the chapters contain, one
+// copy each, as pyftsubset's --text-file input.
+func usedRunes(chapters []Chapter) string {
+	seen := map[rune]bool{}
+	var b strings.Builder
+	for _, ch := range chapters {
+		for _, r := range string(ch.Content) {
+			if !seen[r] {
+				seen[r] = true
+				b.WriteRune(r)
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,86 @@
+package epub
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// ReadMetadata extracts the Dublin Core metadata from an existing EPUB,
+// used when adopting externally added files into the library.
+func ReadMetadata(epubPath string) (Metadata, error) {
+	var meta Metadata
+	zr, err := zip.OpenReader(epubPath)
+	if err != nil {
+		return meta, fmt.Errorf("opening %s: %w", epubPath, err)
+	}
+	defer zr.Close()
+
+	opfPath, err := rootfilePath(&zr.Reader)
+	if err != nil {
+		return meta, err
+	}
+	f, err := openZipFile(&zr.Reader, opfPath)
+	if err != nil {
+		return meta, err
+	}
+	defer f.Close()
+
+	var pkg struct {
+		Metadata struct {
+			Title       string   `xml:"title"`
+			Creators    []string `xml:"creator"`
+			Identifiers []string `xml:"identifier"`
+			Publisher   string   `xml:"publisher"`
+			Language    string   `xml:"language"`
+			Description string   `xml:"description"`
+		} `xml:"metadata"`
+	}
+	if err := xml.NewDecoder(f).Decode(&pkg); err != nil {
+		return meta, fmt.Errorf("parsing %s: %w", opfPath, err)
+	}
+	meta.Title = strings.TrimSpace(pkg.Metadata.Title)
+	meta.Authors = pkg.Metadata.Creators
+	meta.Publisher = pkg.Metadata.Publisher
+	meta.Language = pkg.Metadata.Language
+	meta.Description = pkg.Metadata.Description
+	if len(pkg.Metadata.Identifiers) > 0 {
+		meta.Identifier = strings.TrimSpace(pkg.Metadata.Identifiers[0])
+	}
+	return meta, nil
+}
+
+// rootfilePath reads META-INF/container.xml to find the OPF document.
+func rootfilePath(zr *zip.Reader) (string, error) {
+	f, err := openZipFile(zr, "META-INF/container.xml")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	var container struct {
+		Rootfiles struct {
+			Rootfile []struct {
+				FullPath string `xml:"full-path,attr"`
+			} `xml:"rootfile"`
+		} `xml:"rootfiles"`
+	}
+	if err := xml.NewDecoder(f).Decode(&container); err != nil {
+		return "", fmt.Errorf("parsing container.xml: %w", err)
+	}
+	if len(container.Rootfiles.Rootfile) == 0 {
+		return "", fmt.Errorf("container.xml names no rootfile")
+	}
+	return container.Rootfiles.Rootfile[0].FullPath, nil
+}
+
+func openZipFile(zr *zip.Reader, name string) (io.ReadCloser, error) {
+	for _, f := range zr.File {
+		if path.Clean(f.Name) == path.Clean(name) {
+			return f.Open()
+		}
+	}
+	return nil, fmt.Errorf("no %s entry in archive", name)
+}
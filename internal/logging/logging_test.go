@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	long := "eyJhbGciOiJIUzI1NiJ9.payload.signature"
+	got := Redact(long)
+	if strings.Contains(got, "payload") {
+		t.Errorf("Redact leaked the secret: %q", got)
+	}
+	if !strings.HasPrefix(got, long[:4]) {
+		t.Errorf("Redact dropped the correlation prefix: %q", got)
+	}
+	if Redact("short") == "short" {
+		t.Error("Redact left a short secret intact")
+	}
+	if Redact("") != "" {
+		t.Error("Redact invented a value for the empty string")
+	}
+}
+
+func TestHandlerRedactsSecretKeys(t *testing.T) {
+	var buf bytes.Buffer
+	text := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	logger := slog.New(&redactingHandler{inner: text})
+
+	secret := "super-secret-session-token-value"
+	logger.Info("login", "orm_jwt", secret, "Authorization", "Bearer "+secret, "user", "reader@example.com")
+
+	out := buf.String()
+	if strings.Contains(out, secret) {
+		t.Errorf("secret value reached the output: %s", out)
+	}
+	if !strings.Contains(out, "reader@example.com") {
+		t.Errorf("non-secret attribute was lost: %s", out)
+	}
+}
+
+func TestHandlerRedactsWithAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	text := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	logger := slog.New(&redactingHandler{inner: text}).With("cookie", "orm-jwt=abcdefghijklmnop")
+
+	logger.Info("request")
+	if strings.Contains(buf.String(), "abcdefghijklmnop") {
+		t.Errorf("cookie value reached the output: %s", buf.String())
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	cases := map[string]slog.Level{
+		"debug":   slog.LevelDebug,
+		"warn":    slog.LevelWarn,
+		"error":   slog.LevelError,
+		"info":    slog.LevelInfo,
+		"bogus":   slog.LevelInfo,
+		"WARNING": slog.LevelWarn,
+	}
+	for name, want := range cases {
+		if got := ParseLevel(name); got != want {
+			t.Errorf("ParseLevel(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// Package manifest reads pinned book lists that teams keep in git so a
+// shared reference library can be reproduced exactly.
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Entry pins one book. SHA256 is optional; when set, the downloaded
+// file must match it.
+type Entry struct {
+	ID     string `yaml:"id"`
+	Title  string `yaml:"title,omitempty"`
+	Format string `yaml:"format,omitempty"`
+	SHA256 string `yaml:"sha256,omitempty"`
+}
+
+// Manifest is a pinned book list.
+type Manifest struct {
+	Books []Entry `yaml:"books"`
+}
+
+// Load parses a manifest file.
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading manifest: %w", err)
+	}
+	var m Manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parsing manifest: %w", err)
+	}
+	for i, e := range m.Books {
+		if e.ID == "" {
+			return nil, fmt.Errorf("manifest entry %d has no id", i+1)
+		}
+	}
+	return &m, nil
+}
+
+// FileSHA256 returns the hex SHA-256 of a file on disk.
+func FileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,167 @@
+// Package metrics implements the client's Recorder interface with
+// Prometheus-style counters and histograms, exposed over HTTP in the
+// text exposition format. It hand-rolls the format rather than pulling
+// in the Prometheus client library; the handful of series goreilly
+// emits doesn't justify the dependency.
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// durationBuckets are the histogram bounds for request durations, in
+// seconds.
+var durationBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// hostHistogram is one host's request duration histogram.
+type hostHistogram struct {
+	counts []uint64 // one per bucket, plus +Inf
+	sum    float64
+	total  uint64
+}
+
+// Prometheus collects client events and serves them at /metrics.
+// It is safe for concurrent use.
+type Prometheus struct {
+	mu        sync.Mutex
+	requests  map[string]uint64 // "host status" -> count
+	retries   map[string]uint64
+	waits     map[string]float64 // seconds spent waiting per host
+	waitCount map[string]uint64
+	bytes     map[string]uint64
+	durations map[string]*hostHistogram
+}
+
+// NewPrometheus returns an empty collector.
+func NewPrometheus() *Prometheus {
+	return &Prometheus{
+		requests:  map[string]uint64{},
+		retries:   map[string]uint64{},
+		waits:     map[string]float64{},
+		waitCount: map[string]uint64{},
+		bytes:     map[string]uint64{},
+		durations: map[string]*hostHistogram{},
+	}
+}
+
+// Request implements client.Recorder.
+func (p *Prometheus) Request(host string, status int, duration time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.requests[fmt.Sprintf("%s %d", host, status)]++
+	h := p.durations[host]
+	if h == nil {
+		h = &hostHistogram{counts: make([]uint64, len(durationBuckets)+1)}
+		p.durations[host] = h
+	}
+	secs := duration.Seconds()
+	idx := len(durationBuckets)
+	for i, bound := range durationBuckets {
+		if secs <= bound {
+			idx = i
+			break
+		}
+	}
+	h.counts[idx]++
+	h.sum += secs
+	h.total++
+}
+
+// Retry implements client.Recorder.
+func (p *Prometheus) Retry(host string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.retries[host]++
+}
+
+// RateLimitWait implements client.Recorder.
+func (p *Prometheus) RateLimitWait(host string, wait time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.waits[host] += wait.Seconds()
+	p.waitCount[host]++
+}
+
+// BodyBytes implements client.Recorder.
+func (p *Prometheus) BodyBytes(host string, n int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.bytes[host] += uint64(n)
+}
+
+// ServeHTTP renders the collected series in the text exposition format.
+func (p *Prometheus) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var b strings.Builder
+
+	b.WriteString("# HELP goreilly_http_requests_total HTTP request attempts by host and status (status 0 is a transport error).\n")
+	b.WriteString("# TYPE goreilly_http_requests_total counter\n")
+	for _, key := range sortedKeys(p.requests) {
+		host, status, _ := strings.Cut(key, " ")
+		fmt.Fprintf(&b, "goreilly_http_requests_total{host=%q,status=%q} %d\n", host, status, p.requests[key])
+	}
+
+	b.WriteString("# HELP goreilly_http_retries_total Request attempts that were retried.\n")
+	b.WriteString("# TYPE goreilly_http_retries_total counter\n")
+	for _, host := range sortedKeys(p.retries) {
+		fmt.Fprintf(&b, "goreilly_http_retries_total{host=%q} %d\n", host, p.retries[host])
+	}
+
+	b.WriteString("# HELP goreilly_rate_limit_wait_seconds_total Time spent waiting on per-host rate limiters.\n")
+	b.WriteString("# TYPE goreilly_rate_limit_wait_seconds_total counter\n")
+	for _, host := range sortedFloatKeys(p.waits) {
+		fmt.Fprintf(&b, "goreilly_rate_limit_wait_seconds_total{host=%q} %g\n", host, p.waits[host])
+	}
+
+	b.WriteString("# HELP goreilly_downloaded_bytes_total Response body bytes read, by host.\n")
+	b.WriteString("# TYPE goreilly_downloaded_bytes_total counter\n")
+	for _, host := range sortedKeys(p.bytes) {
+		fmt.Fprintf(&b, "goreilly_downloaded_bytes_total{host=%q} %d\n", host, p.bytes[host])
+	}
+
+	b.WriteString("# HELP goreilly_http_request_duration_seconds Request attempt duration, by host.\n")
+	b.WriteString("# TYPE goreilly_http_request_duration_seconds histogram\n")
+	hosts := make([]string, 0, len(p.durations))
+	for host := range p.durations {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	for _, host := range hosts {
+		h := p.durations[host]
+		var cumulative uint64
+		for i, bound := range durationBuckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(&b, "goreilly_http_request_duration_seconds_bucket{host=%q,le=%q} %d\n", host, fmt.Sprintf("%g", bound), cumulative)
+		}
+		fmt.Fprintf(&b, "goreilly_http_request_duration_seconds_bucket{host=%q,le=\"+Inf\"} %d\n", host, h.total)
+		fmt.Fprintf(&b, "goreilly_http_request_duration_seconds_sum{host=%q} %g\n", host, h.sum)
+		fmt.Fprintf(&b, "goreilly_http_request_duration_seconds_count{host=%q} %d\n", host, h.total)
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	fmt.Fprint(w, b.String())
+}
+
+func sortedKeys(m map[string]uint64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedFloatKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
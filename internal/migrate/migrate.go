@@ -0,0 +1,87 @@
+// Package migrate versions goreilly's on-disk state and upgrades older
+// layouts in place. Every command runs pending migrations on startup;
+// a state directory written by a newer goreilly is refused outright
+// rather than silently corrupted.
+package migrate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/tuannvm/koreilly/internal/config"
+)
+
+// versionFile records the state layout version inside the data dir.
+const versionFile = "state_version"
+
+// A migration upgrades state from version-1 to version. Migrations must
+// be idempotent: an interrupted run is simply retried on next startup.
+type migration struct {
+	version int
+	what    string
+	run     func() error
+}
+
+// migrations, in order. Append only; never renumber.
+var migrations = []migration{
+	{1, "move legacy ~/.config/goreilly layout to platform directories", config.MigrateLegacy},
+}
+
+// CurrentVersion is the layout this build reads and writes.
+func CurrentVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+// Run upgrades the state directory to the current version, applying any
+// pending migrations in order. It fails without touching anything when
+// the directory was written by a newer goreilly.
+func Run() error {
+	have, err := readVersion()
+	if err != nil {
+		return err
+	}
+	current := CurrentVersion()
+	if have > current {
+		return fmt.Errorf("state in %s is version %d, newer than this goreilly understands (%d); upgrade goreilly or point GOREILLY_PROFILE elsewhere", config.DataDir(), have, current)
+	}
+	for _, m := range migrations {
+		if m.version <= have {
+			continue
+		}
+		if err := m.run(); err != nil {
+			return fmt.Errorf("state migration to v%d (%s): %w", m.version, m.what, err)
+		}
+		if err := writeVersion(m.version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readVersion returns the recorded state version; a missing marker
+// means version 0, i.e. run everything.
+func readVersion() (int, error) {
+	raw, err := os.ReadFile(filepath.Join(config.DataDir(), versionFile))
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("reading state version: %w", err)
+	}
+	v, err := strconv.Atoi(strings.TrimSpace(string(raw)))
+	if err != nil {
+		return 0, fmt.Errorf("corrupt state version marker %q in %s", strings.TrimSpace(string(raw)), config.DataDir())
+	}
+	return v, nil
+}
+
+func writeVersion(v int) error {
+	dir := config.DataDir()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, versionFile), []byte(strconv.Itoa(v)+"\n"), 0o644)
+}
@@ -0,0 +1,60 @@
+package migrate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func setStateDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "config"))
+	t.Setenv("XDG_DATA_HOME", filepath.Join(dir, "data"))
+	return filepath.Join(dir, "data", "goreilly")
+}
+
+func TestRunStampsCurrentVersion(t *testing.T) {
+	dataDir := setStateDir(t)
+	if err := Run(); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	raw, err := os.ReadFile(filepath.Join(dataDir, versionFile))
+	if err != nil {
+		t.Fatalf("version marker not written: %v", err)
+	}
+	if string(raw) != "1\n" {
+		t.Errorf("marker = %q, want %q", raw, "1\n")
+	}
+	// A second run over current state is a no-op.
+	if err := Run(); err != nil {
+		t.Fatalf("second Run: %v", err)
+	}
+}
+
+func TestRunRefusesNewerState(t *testing.T) {
+	dataDir := setStateDir(t)
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dataDir, versionFile), []byte("99\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Run(); err == nil {
+		t.Fatal("Run should refuse a newer state dir")
+	}
+}
+
+func TestRunRejectsCorruptMarker(t *testing.T) {
+	dataDir := setStateDir(t)
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dataDir, versionFile), []byte("banana"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Run(); err == nil {
+		t.Fatal("Run should reject a corrupt version marker")
+	}
+}
@@ -0,0 +1,78 @@
+// Package netmeter detects whether the active network connection is
+// metered, so large downloads can pause instead of burning through a
+// hotspot data plan.
+package netmeter
+
+import (
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Status describes the metered check result.
+type Status struct {
+	Metered bool
+	// Reason explains how the decision was made ("nmcli reports
+	// metered", "connected to listed hotspot SSID ...", etc.).
+	Reason string
+}
+
+// Detect reports whether the current connection looks metered.
+// hotspotSSIDs is a user-configured list of wifi networks to always
+// treat as metered. Detection is best-effort: when the platform offers
+// no signal, the connection is assumed unmetered.
+func Detect(hotspotSSIDs []string) Status {
+	if ssid := currentSSID(); ssid != "" {
+		for _, listed := range hotspotSSIDs {
+			if strings.EqualFold(ssid, listed) {
+				return Status{Metered: true, Reason: "connected to listed hotspot SSID " + ssid}
+			}
+		}
+	}
+	switch runtime.GOOS {
+	case "linux":
+		return detectLinux()
+	default:
+		return Status{}
+	}
+}
+
+// detectLinux asks NetworkManager via nmcli, the common desktop case.
+func detectLinux() Status {
+	nmcli, err := exec.LookPath("nmcli")
+	if err != nil {
+		return Status{}
+	}
+	out, err := exec.Command(nmcli, "-t", "-f", "GENERAL.METERED", "general").Output()
+	if err != nil {
+		return Status{}
+	}
+	value := strings.ToLower(strings.TrimSpace(string(out)))
+	if strings.Contains(value, "yes") {
+		return Status{Metered: true, Reason: "NetworkManager reports the connection as metered"}
+	}
+	return Status{}
+}
+
+// currentSSID returns the connected wifi network name, if detectable.
+func currentSSID() string {
+	switch runtime.GOOS {
+	case "linux":
+		if iwgetid, err := exec.LookPath("iwgetid"); err == nil {
+			if out, err := exec.Command(iwgetid, "-r").Output(); err == nil {
+				return strings.TrimSpace(string(out))
+			}
+		}
+	case "darwin":
+		airport := "/System/Library/PrivateFrameworks/Apple80211.framework/Versions/Current/Resources/airport"
+		if out, err := exec.Command(airport, "-I").Output(); err == nil {
+			for _, line := range strings.Split(string(out), "\n") {
+				line = strings.TrimSpace(line)
+				if strings.HasPrefix(line, "SSID:") {
+					return strings.TrimSpace(strings.TrimPrefix(line, "SSID:"))
+				}
+			}
+		}
+	}
+	return ""
+}
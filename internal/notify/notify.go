@@ -0,0 +1,51 @@
+// Package notify delivers short alerts through the channels watch mode
+// supports: desktop notifications and webhooks. Email alerts reuse the
+// delivery package's SMTP configuration.
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"runtime"
+
+	"github.com/tuannvm/koreilly/internal/client"
+)
+
+// Desktop shows a notification through the platform's native
+// mechanism: osascript on macOS, notify-send elsewhere.
+func Desktop(title, body string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %q with title %q", body, title)
+		cmd = exec.Command("osascript", "-e", script)
+	case "windows":
+		return fmt.Errorf("desktop notifications are not supported on windows; use --webhook or --email")
+	default:
+		cmd = exec.Command("notify-send", title, body)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("desktop notification failed: %v (%s)", err, out)
+	}
+	return nil
+}
+
+// Webhook POSTs payload as JSON to url — the shape Slack-compatible
+// receivers and home-grown hooks both accept.
+func Webhook(ctx context.Context, url string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	resp, err := client.New().Post(ctx, url, nil, body)
+	if err != nil {
+		return fmt.Errorf("webhook request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+// Package opds serves the local library as an OPDS 1.2 catalog so
+// e-reader apps (KOReader, Moon+ Reader, Calibre) can browse and fetch
+// books straight from the machine running goreilly.
+package opds
+
+import (
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tuannvm/koreilly/internal/catalog"
+)
+
+// Atom/OPDS feed structures (the subset OPDS 1.2 needs).
+type feed struct {
+	XMLName xml.Name `xml:"feed"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	ID      string   `xml:"id"`
+	Title   string   `xml:"title"`
+	Updated string   `xml:"updated"`
+	Links   []link   `xml:"link"`
+	Entries []entry  `xml:"entry"`
+}
+
+type link struct {
+	Rel  string `xml:"rel,attr"`
+	Href string `xml:"href,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type entry struct {
+	ID      string   `xml:"id"`
+	Title   string   `xml:"title"`
+	Authors []author `xml:"author"`
+	Updated string   `xml:"updated"`
+	Links   []link   `xml:"link"`
+}
+
+type author struct {
+	Name string `xml:"name"`
+}
+
+const (
+	acquisitionRel  = "http://opds-spec.org/acquisition"
+	coverRel        = "http://opds-spec.org/image"
+	acquisitionType = "application/atom+xml;profile=opds-catalog;kind=acquisition"
+)
+
+// Server renders the catalog as an OPDS feed and serves book files.
+type Server struct {
+	catalog *catalog.Catalog
+	mux     *http.ServeMux
+}
+
+// New builds the OPDS server over the given catalog.
+func New(cat *catalog.Catalog) *Server {
+	s := &Server{catalog: cat, mux: http.NewServeMux()}
+	s.mux.HandleFunc("/opds", s.handleFeed)
+	s.mux.HandleFunc("/opds/", s.handleFeed)
+	s.mux.HandleFunc("/books/", s.handleFile)
+	s.mux.HandleFunc("/covers/", s.handleCover)
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) handleFeed(w http.ResponseWriter, r *http.Request) {
+	records, err := s.catalog.List()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	f := feed{
+		Xmlns:   "http://www.w3.org/2005/Atom",
+		ID:      "urn:goreilly:catalog",
+		Title:   "goreilly library",
+		Updated: now,
+		Links: []link{
+			{Rel: "self", Href: "/opds", Type: acquisitionType},
+			{Rel: "start", Href: "/opds", Type: acquisitionType},
+		},
+	}
+	for _, rec := range records {
+		e := entry{
+			ID:      "urn:goreilly:book:" + rec.ID,
+			Title:   rec.Title,
+			Updated: rec.DownloadedAt.UTC().Format(time.RFC3339),
+		}
+		for _, a := range rec.Authors {
+			e.Authors = append(e.Authors, author{Name: a})
+		}
+		e.Links = append(e.Links, link{
+			Rel:  acquisitionRel,
+			Href: fmt.Sprintf("/books/%s/%s", rec.ID, rec.Format),
+			Type: mediaType(rec.Format),
+		})
+		if cover := coverPath(rec); cover != "" {
+			e.Links = append(e.Links, link{
+				Rel:  coverRel,
+				Href: fmt.Sprintf("/covers/%s", rec.ID),
+				Type: mime.TypeByExtension(filepath.Ext(cover)),
+			})
+		}
+		f.Entries = append(f.Entries, e)
+	}
+	w.Header().Set("Content-Type", acquisitionType)
+	w.Write([]byte(xml.Header))
+	xml.NewEncoder(w).Encode(f)
+}
+
+// handleFile serves /books/{id}/{format}.
+func (s *Server) handleFile(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/books/"), "/")
+	if len(parts) != 2 {
+		http.Error(w, "want /books/{id}/{format}", http.StatusBadRequest)
+		return
+	}
+	rec, err := s.catalog.Get(parts[0], parts[1])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if rec == nil {
+		http.NotFound(w, r)
+		return
+	}
+	http.ServeFile(w, r, rec.Path)
+}
+
+// handleCover serves /covers/{id}, looking in every known format record.
+func (s *Server) handleCover(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/covers/")
+	records, err := s.catalog.List()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for _, rec := range records {
+		if rec.ID != id {
+			continue
+		}
+		if cover := coverPath(rec); cover != "" {
+			http.ServeFile(w, r, cover)
+			return
+		}
+	}
+	http.NotFound(w, r)
+}
+
+// coverPath returns a cover image saved next to the book file, if any.
+func coverPath(rec catalog.Record) string {
+	base := strings.TrimSuffix(rec.Path, filepath.Ext(rec.Path))
+	for _, ext := range []string{".jpg", ".jpeg", ".png"} {
+		if _, err := os.Stat(base + ext); err == nil {
+			return base + ext
+		}
+	}
+	return ""
+}
+
+func mediaType(format string) string {
+	switch format {
+	case "epub":
+		return "application/epub+zip"
+	case "pdf":
+		return "application/pdf"
+	case "mobi":
+		return "application/x-mobipocket-ebook"
+	default:
+		return "application/octet-stream"
+	}
+}
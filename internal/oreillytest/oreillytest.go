@@ -0,0 +1,243 @@
+// Package oreillytest fakes the subset of the O'Reilly API goreilly
+// talks to — login, search, book detail, TOC, chapter content,
+// playlists, and progress — with canned fixtures, so the auth, service,
+// and downloader layers can be exercised in tests without credentials
+// or network. Command mockoreilly serves the same handler as a
+// standalone process for poking at the CLI and TUI by hand.
+package oreillytest
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/tuannvm/koreilly/internal/services/oreilly"
+)
+
+// Fixtures are the titles the fake API knows about.
+var Fixtures = []oreilly.Book{
+	{
+		ProductID:    "9780000000001",
+		Title:        "Mock Distributed Systems",
+		Authors:      []string{"Ada Fixture"},
+		ISBN:         "9780000000001",
+		Publishers:   []string{"Mock Press"},
+		Format:       "book",
+		Issued:       "2024-03-01",
+		Description:  "<p>A canned book used to exercise goreilly end to end.</p>",
+		VirtualPages: 320,
+		Topics:       []string{"distributed systems", "testing"},
+	},
+	{
+		ProductID:    "9780000000002",
+		Title:        "Mock Go Patterns",
+		Authors:      []string{"Grace Stub", "Ada Fixture"},
+		ISBN:         "9780000000002",
+		Publishers:   []string{"Mock Press"},
+		Format:       "book",
+		Issued:       "2023-11-15",
+		Description:  "<p>Another fixture title with three short chapters.</p>",
+		VirtualPages: 180,
+		Topics:       []string{"go"},
+	},
+}
+
+// ChapterTitles are the chapters every fixture book serves.
+var ChapterTitles = []string{"Introduction", "The Middle Part", "Conclusion"}
+
+// Playlists are canned collections referencing the fixture titles.
+var Playlists = []oreilly.Playlist{
+	{
+		ID:          "pl-0001",
+		Name:        "Mock Essentials",
+		Description: "Both fixture titles in one playlist.",
+		IsOwned:     true,
+		Content: []oreilly.PlaylistItem{
+			{ProductID: "9780000000001", Title: "Mock Distributed Systems", Format: "book"},
+			{ProductID: "9780000000002", Title: "Mock Go Patterns", Format: "book"},
+		},
+	},
+	{
+		ID:          "pl-0002",
+		Name:        "Followed Fixtures",
+		Description: "A followed playlist with one title.",
+		Following:   true,
+		Content: []oreilly.PlaylistItem{
+			{ProductID: "9780000000002", Title: "Mock Go Patterns", Format: "book"},
+		},
+	},
+}
+
+// NewServer starts a fixture server and points GOREILLY_BASE_URL at it
+// for the duration of the test, so code using the service layer talks
+// to the fake transparently.
+func NewServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(Handler())
+	t.Cleanup(srv.Close)
+	t.Setenv("GOREILLY_BASE_URL", srv.URL)
+	return srv
+}
+
+// Handler returns the fake API. Each handler carries its own mutable
+// state (posted progress), so servers are independent.
+func Handler() http.Handler {
+	s := &server{progress: map[string]float64{"9780000000001": 0.4}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/member/auth/login/", s.handleLogin)
+	mux.HandleFunc("/api/v2/search/", s.handleSearch)
+	mux.HandleFunc("/api/v1/book/", s.handleBookDetail)
+	mux.HandleFunc("/api/v2/epubs/", s.handleEpub)
+	mux.HandleFunc("/api/v2/playlists/", s.handlePlaylists)
+	mux.HandleFunc("/api/v1/progress/", s.handleProgress)
+	return mux
+}
+
+// server holds the per-instance mutable state behind the handler.
+type server struct {
+	mu       sync.Mutex
+	progress map[string]float64
+}
+
+func (s *server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var creds struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&creds); err != nil || creds.Email == "" {
+		http.Error(w, "bad credentials payload", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, map[string]string{"id_token": "mock-jwt-" + creds.Email})
+}
+
+func (s *server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	query := strings.ToLower(r.URL.Query().Get("query"))
+	var hits []oreilly.Book
+	for _, b := range Fixtures {
+		if query == "" || strings.Contains(strings.ToLower(b.Title), query) {
+			hits = append(hits, b)
+		}
+	}
+	writeJSON(w, oreilly.SearchResult{Total: len(hits), Results: hits})
+}
+
+func (s *server) handleBookDetail(w http.ResponseWriter, r *http.Request) {
+	// /api/v1/book/<id>/
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/book/"), "/")
+	if book := findBook(id); book != nil {
+		writeJSON(w, book)
+		return
+	}
+	http.NotFound(w, r)
+}
+
+// handleEpub serves /api/v2/epubs/urn:orm:book:<id> (HEAD for size
+// estimates), its navigation.xhtml, and chapter files.
+func (s *server) handleEpub(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/api/v2/epubs/urn:orm:book:")
+	id, file, _ := strings.Cut(rest, "/files/")
+	id = strings.TrimSuffix(id, "/")
+	book := findBook(id)
+	if book == nil {
+		http.NotFound(w, r)
+		return
+	}
+	switch {
+	case file == "":
+		w.Header().Set("Content-Length", "1048576")
+	case file == "navigation.xhtml":
+		fmt.Fprint(w, navDocument())
+	case strings.HasPrefix(file, "ch") && strings.HasSuffix(file, ".html"):
+		n := 0
+		fmt.Sscanf(file, "ch%02d.html", &n)
+		if n < 1 || n > len(ChapterTitles) {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, "<html><body><h1>%s</h1><p>Chapter %d of %s. Lorem ipsum dolor sit amet.</p><pre>fmt.Println(%q)</pre></body></html>",
+			ChapterTitles[n-1], n, book.Title, book.Title)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func navDocument() string {
+	var b strings.Builder
+	b.WriteString(`<html><body><nav epub:type="toc"><ol>`)
+	for i, title := range ChapterTitles {
+		fmt.Fprintf(&b, `<li><a href="ch%02d.html">%s</a></li>`, i+1, title)
+	}
+	b.WriteString(`</ol></nav></body></html>`)
+	return b.String()
+}
+
+// handlePlaylists serves the listing at /api/v2/playlists/ and single
+// playlists at /api/v2/playlists/<id>/.
+func (s *server) handlePlaylists(w http.ResponseWriter, r *http.Request) {
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v2/playlists/"), "/")
+	if id == "" {
+		writeJSON(w, struct {
+			Results []oreilly.Playlist `json:"results"`
+			Next    string             `json:"next"`
+		}{Results: Playlists})
+		return
+	}
+	for i := range Playlists {
+		if Playlists[i].ID == id {
+			writeJSON(w, Playlists[i])
+			return
+		}
+	}
+	http.NotFound(w, r)
+}
+
+// handleProgress serves the listing at /api/v1/progress/ and accepts
+// position updates at /api/v1/progress/<id>/.
+func (s *server) handleProgress(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r.Method == http.MethodPost {
+		var body oreilly.BookProgress
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.BookID == "" {
+			http.Error(w, "bad progress payload", http.StatusBadRequest)
+			return
+		}
+		s.progress[body.BookID] = body.Percent
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	var results []oreilly.BookProgress
+	for id, pct := range s.progress {
+		results = append(results, oreilly.BookProgress{BookID: id, Percent: pct})
+	}
+	writeJSON(w, struct {
+		Results []oreilly.BookProgress `json:"results"`
+		Next    string                 `json:"next"`
+	}{Results: results})
+}
+
+func findBook(id string) *oreilly.Book {
+	for i := range Fixtures {
+		if Fixtures[i].ProductID == id || Fixtures[i].ISBN == id {
+			return &Fixtures[i]
+		}
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("encoding response: %v", err)
+	}
+}
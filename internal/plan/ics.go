@@ -0,0 +1,38 @@
+package plan
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const icsTimeLayout = "20060102T150405Z"
+
+// WriteICS renders the plan as an iCalendar file that calendar apps can
+// import directly.
+func (p *Plan) WriteICS(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("BEGIN:VCALENDAR\r\n")
+	b.WriteString("VERSION:2.0\r\n")
+	b.WriteString("PRODID:-//goreilly//reading plan//EN\r\n")
+	now := time.Now().UTC().Format(icsTimeLayout)
+	for i, s := range p.Sessions {
+		b.WriteString("BEGIN:VEVENT\r\n")
+		fmt.Fprintf(&b, "UID:goreilly-%s-%d@goreilly\r\n", p.BookID, i)
+		fmt.Fprintf(&b, "DTSTAMP:%s\r\n", now)
+		fmt.Fprintf(&b, "DTSTART:%s\r\n", s.Start.UTC().Format(icsTimeLayout))
+		fmt.Fprintf(&b, "DTEND:%s\r\n", s.Start.Add(s.Duration).UTC().Format(icsTimeLayout))
+		fmt.Fprintf(&b, "SUMMARY:%s\r\n", escapeICS(s.Title))
+		b.WriteString("END:VEVENT\r\n")
+	}
+	b.WriteString("END:VCALENDAR\r\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// escapeICS escapes the characters RFC 5545 requires in text values.
+func escapeICS(s string) string {
+	r := strings.NewReplacer("\\", "\\\\", ";", "\\;", ",", "\\,", "\n", "\\n")
+	return r.Replace(s)
+}
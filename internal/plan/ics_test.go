@@ -0,0 +1,55 @@
+package plan
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tuannvm/koreilly/internal/services/oreilly"
+)
+
+func TestBuildSchedulesSessionsPerDay(t *testing.T) {
+	chapters := []oreilly.Chapter{
+		{Title: "One", Order: 1},
+		{Title: "Two", Order: 2},
+		{Title: "Three", Order: 3},
+	}
+	start := time.Date(2024, 3, 1, 19, 0, 0, 0, time.UTC)
+	p := Build("123", "Test Book", chapters, start, 45*time.Minute, 2)
+
+	if len(p.Sessions) != 3 {
+		t.Fatalf("got %d sessions, want 3", len(p.Sessions))
+	}
+	if !p.Sessions[0].Start.Equal(start) {
+		t.Errorf("first session starts at %v, want %v", p.Sessions[0].Start, start)
+	}
+	if got, want := p.Sessions[2].Start, start.AddDate(0, 0, 1); !got.Equal(want) {
+		t.Errorf("third session starts at %v, want next day %v", got, want)
+	}
+}
+
+func TestWriteICS(t *testing.T) {
+	p := &Plan{
+		BookID: "123",
+		Title:  "Test Book",
+		Sessions: []Session{
+			{Title: "Test Book — Intro; part one", Start: time.Date(2024, 3, 1, 19, 0, 0, 0, time.UTC), Duration: 30 * time.Minute},
+		},
+	}
+	var b strings.Builder
+	if err := p.WriteICS(&b); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"BEGIN:VCALENDAR",
+		"DTSTART:20240301T190000Z",
+		"DTEND:20240301T193000Z",
+		`SUMMARY:Test Book — Intro\; part one`,
+		"END:VCALENDAR",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("ICS output missing %q", want)
+		}
+	}
+}
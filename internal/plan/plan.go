@@ -0,0 +1,77 @@
+// Package plan builds reading plans from a book's chapter list and
+// exports them to calendar formats.
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tuannvm/koreilly/internal/config"
+	"github.com/tuannvm/koreilly/internal/services/oreilly"
+)
+
+// Session is a single scheduled reading block.
+type Session struct {
+	Title    string        `json:"title"`
+	Start    time.Time     `json:"start"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Plan is a scheduled reading plan for one book.
+type Plan struct {
+	BookID   string    `json:"book_id"`
+	Title    string    `json:"title"`
+	Sessions []Session `json:"sessions"`
+}
+
+// Build schedules one session per chapter starting at start, sessionsPerDay
+// sessions each day, each lasting duration.
+func Build(bookID, title string, chapters []oreilly.Chapter, start time.Time, duration time.Duration, sessionsPerDay int) *Plan {
+	if sessionsPerDay < 1 {
+		sessionsPerDay = 1
+	}
+	p := &Plan{BookID: bookID, Title: title}
+	for i, ch := range chapters {
+		day := i / sessionsPerDay
+		slot := i % sessionsPerDay
+		begin := start.AddDate(0, 0, day).Add(time.Duration(slot) * (duration + 30*time.Minute))
+		p.Sessions = append(p.Sessions, Session{
+			Title:    fmt.Sprintf("%s — %s", title, ch.Title),
+			Start:    begin,
+			Duration: duration,
+		})
+	}
+	return p
+}
+
+func path() string {
+	return filepath.Join(config.DataDir(), "plan.json")
+}
+
+// Save persists the plan to the config directory.
+func (p *Plan) Save() error {
+	if err := os.MkdirAll(config.DataDir(), 0o755); err != nil {
+		return fmt.Errorf("creating config dir: %w", err)
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path(), data, 0o644)
+}
+
+// Load reads the previously saved plan.
+func Load() (*Plan, error) {
+	data, err := os.ReadFile(path())
+	if err != nil {
+		return nil, fmt.Errorf("no saved plan (run goreilly plan first): %w", err)
+	}
+	var p Plan
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parsing plan: %w", err)
+	}
+	return &p, nil
+}
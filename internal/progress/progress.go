@@ -0,0 +1,111 @@
+// Package progress records where the user last stopped reading each
+// book, locally and independent of network availability.
+package progress
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/tuannvm/koreilly/internal/config"
+)
+
+// Position is the last read location in one book.
+type Position struct {
+	BookID     string    `json:"book_id"`
+	BookTitle  string    `json:"book_title"`
+	Chapter    string    `json:"chapter"`
+	ChapterIdx int       `json:"chapter_idx"`
+	Offset     int       `json:"offset"`  // viewport scroll offset in lines
+	Percent    float64   `json:"percent"` // fraction of the book finished, 0..1
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Store reads and writes the progress file.
+type Store struct {
+	path string
+}
+
+// NewStore returns a Store over the default progress file.
+func NewStore() *Store {
+	return &Store{path: filepath.Join(config.DataDir(), "progress.json")}
+}
+
+func (s *Store) load() (map[string]Position, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return map[string]Position{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	positions := map[string]Position{}
+	if err := json.Unmarshal(data, &positions); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", s.path, err)
+	}
+	return positions, nil
+}
+
+// Save records the position for its book and marks it as most recent.
+func (s *Store) Save(pos Position) error {
+	pos.UpdatedAt = time.Now()
+	positions, err := s.load()
+	if err != nil {
+		return err
+	}
+	positions[pos.BookID] = pos
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(positions, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0o644)
+}
+
+// Last returns the most recently updated position, or nil when nothing
+// has been read yet.
+func (s *Store) Last() (*Position, error) {
+	positions, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	var last *Position
+	for id := range positions {
+		pos := positions[id]
+		if last == nil || pos.UpdatedAt.After(last.UpdatedAt) {
+			last = &pos
+		}
+	}
+	return last, nil
+}
+
+// All returns every saved position, most recently updated first.
+func (s *Store) All() ([]Position, error) {
+	positions, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Position, 0, len(positions))
+	for _, pos := range positions {
+		out = append(out, pos)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].UpdatedAt.After(out[j].UpdatedAt) })
+	return out, nil
+}
+
+// ForBook returns the saved position for one book, or nil.
+func (s *Store) ForBook(bookID string) (*Position, error) {
+	positions, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	if pos, ok := positions[bookID]; ok {
+		return &pos, nil
+	}
+	return nil, nil
+}
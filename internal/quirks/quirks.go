@@ -0,0 +1,115 @@
+// Package quirks applies per-publisher fixups to chapter HTML during
+// assembly. Publishers on the platform ship noticeably different
+// markup — image path layouts, heading numbering, callout styles — and
+// a targeted fixup keyed by publisher beats inflating the generic
+// sanitizer with special cases.
+package quirks
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// fixup adjusts one parsed chapter document in place.
+type fixup func(doc *goquery.Document)
+
+// registry maps a lowercase publisher-name fragment to its fixups; a
+// book's publisher is matched by substring, so imprint variations like
+// "Packt Publishing" still hit the "packt" entry.
+var registry = []struct {
+	match string
+	apply fixup
+}{
+	{"packt", packtFixups},
+	{"manning", manningFixups},
+	{"no starch", noStarchFixups},
+}
+
+// Apply runs the fixups registered for the publisher over a sanitized
+// chapter. Unknown publishers and unparseable content pass through
+// unchanged.
+func Apply(publisher string, content []byte) []byte {
+	key := strings.ToLower(publisher)
+	var fns []fixup
+	for _, entry := range registry {
+		if strings.Contains(key, entry.match) {
+			fns = append(fns, entry.apply)
+		}
+	}
+	if len(fns) == 0 {
+		return content
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(content))
+	if err != nil {
+		return content
+	}
+	for _, fn := range fns {
+		fn(doc)
+	}
+	out, err := doc.Html()
+	if err != nil {
+		return content
+	}
+	return []byte(out)
+}
+
+// packtFixups handles Packt markup: images live under graphics/ or
+// assets/ subdirectories that don't survive the flat chapter layout the
+// assembler writes, and callout divs render as unstyled boxes.
+func packtFixups(doc *goquery.Document) {
+	flattenImagePaths(doc)
+	calloutToBlockquote(doc, "div.note, div.tip, div.warning")
+}
+
+// manningHeadingDup matches Manning's duplicated chapter numbers in
+// headings, e.g. "1 1 Introducing things".
+var manningHeadingDup = regexp.MustCompile(`^(\d+)\s+(\d+)\s+`)
+
+// manningFixups handles Manning (via O'Reilly) markup: headings carry
+// the chapter number twice, once from the text and once generated.
+func manningFixups(doc *goquery.Document) {
+	doc.Find("h1, h2").Each(func(_ int, s *goquery.Selection) {
+		text := strings.TrimSpace(s.Text())
+		m := manningHeadingDup.FindStringSubmatch(text)
+		if m != nil && m[1] == m[2] {
+			s.SetText(strings.Replace(text, m[0], m[1]+" ", 1))
+		}
+	})
+	flattenImagePaths(doc)
+}
+
+// noStarchFixups handles No Starch markup: notes are plain paragraphs
+// distinguished only by class, which most readers render as body text.
+func noStarchFixups(doc *goquery.Document) {
+	calloutToBlockquote(doc, "p.note, p.warning")
+}
+
+// flattenImagePaths rewrites relative image sources to their base name,
+// matching the flat file layout chapters are assembled into; absolute
+// URLs are left alone.
+func flattenImagePaths(doc *goquery.Document) {
+	doc.Find("img").Each(func(_ int, s *goquery.Selection) {
+		src, ok := s.Attr("src")
+		if !ok || src == "" || strings.Contains(src, "://") {
+			return
+		}
+		if i := strings.LastIndex(src, "/"); i >= 0 {
+			s.SetAttr("src", src[i+1:])
+		}
+	})
+}
+
+// calloutToBlockquote rewraps callout containers as blockquotes, which
+// every EPUB renderer sets off visually without publisher CSS.
+func calloutToBlockquote(doc *goquery.Document, selector string) {
+	doc.Find(selector).Each(func(_ int, s *goquery.Selection) {
+		inner, err := s.Html()
+		if err != nil {
+			return
+		}
+		s.ReplaceWithHtml("<blockquote>" + inner + "</blockquote>")
+	})
+}
@@ -0,0 +1,32 @@
+package quirks
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestApplyPacktFlattensImagesAndCallouts(t *testing.T) {
+	in := []byte(`<html><body><img src="graphics/B12345_01_01.png"/><div class="note">Heads up.</div></body></html>`)
+	out := string(Apply("Packt Publishing", in))
+	if !strings.Contains(out, `src="B12345_01_01.png"`) {
+		t.Errorf("image path not flattened: %s", out)
+	}
+	if !strings.Contains(out, "<blockquote>") || strings.Contains(out, `class="note"`) {
+		t.Errorf("callout not rewrapped: %s", out)
+	}
+}
+
+func TestApplyManningDedupesHeadingNumbers(t *testing.T) {
+	in := []byte(`<html><body><h1>1 1 Introducing things</h1></body></html>`)
+	out := string(Apply("Manning Publications", in))
+	if !strings.Contains(out, ">1 Introducing things<") {
+		t.Errorf("heading number not deduplicated: %s", out)
+	}
+}
+
+func TestApplyUnknownPublisherPassesThrough(t *testing.T) {
+	in := []byte(`<html><body><img src="graphics/pic.png"/></body></html>`)
+	if out := Apply("O'Reilly Media, Inc.", in); string(out) != string(in) {
+		t.Errorf("unexpected rewrite for unknown publisher: %s", out)
+	}
+}
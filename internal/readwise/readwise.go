@@ -0,0 +1,96 @@
+// Package readwise pushes highlights into a Readwise account and keeps
+// a local log of what was already sent so repeated exports are
+// idempotent.
+package readwise
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/tuannvm/koreilly/internal/client"
+	"github.com/tuannvm/koreilly/internal/config"
+)
+
+// apiURL is Readwise's bulk highlight-create endpoint.
+const apiURL = "https://readwise.io/api/v2/highlights/"
+
+// Highlight mirrors one entry in Readwise's bulk-create payload.
+type Highlight struct {
+	Text          string `json:"text"`
+	Title         string `json:"title,omitempty"`
+	Category      string `json:"category,omitempty"`
+	SourceType    string `json:"source_type,omitempty"`
+	SourceURL     string `json:"source_url,omitempty"`
+	Note          string `json:"note,omitempty"`
+	HighlightedAt string `json:"highlighted_at,omitempty"`
+}
+
+// Push creates highlights in one bulk request, authenticated with the
+// account's API token.
+func Push(ctx context.Context, token string, highlights []Highlight) error {
+	body, err := json.Marshal(struct {
+		Highlights []Highlight `json:"highlights"`
+	}{highlights})
+	if err != nil {
+		return err
+	}
+	headers := http.Header{"Authorization": []string{"Token " + token}}
+	resp, err := client.New().Post(ctx, apiURL, headers, body)
+	if err != nil {
+		return fmt.Errorf("readwise request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("readwise returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SentLog remembers which platform annotation ids were already pushed,
+// so re-running the export never duplicates highlights.
+type SentLog struct {
+	path string
+	ids  map[string]bool
+}
+
+// OpenSentLog loads the log from the data directory; a missing file
+// means nothing was exported yet.
+func OpenSentLog() (*SentLog, error) {
+	log := &SentLog{
+		path: filepath.Join(config.DataDir(), "readwise-sent.json"),
+		ids:  map[string]bool{},
+	}
+	data, err := os.ReadFile(log.path)
+	if os.IsNotExist(err) {
+		return log, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &log.ids); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", log.path, err)
+	}
+	return log, nil
+}
+
+// Has reports whether an annotation id was already exported.
+func (l *SentLog) Has(id string) bool { return l.ids[id] }
+
+// Add marks an annotation id as exported; call Save to persist.
+func (l *SentLog) Add(id string) { l.ids[id] = true }
+
+// Save writes the log back to disk.
+func (l *SentLog) Save() error {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(l.ids, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(l.path, data, 0o644)
+}
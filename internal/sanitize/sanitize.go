@@ -0,0 +1,72 @@
+// Package sanitize cleans publisher chapter HTML before it is shown or
+// packaged. Publisher markup varies wildly and sometimes carries
+// scripts, analytics pixels, or embedded frames that have no place in
+// an offline EPUB.
+package sanitize
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// removedOutright are elements that never survive sanitization.
+const removedOutright = "script, object, embed, form, link[rel=preconnect], link[rel=dns-prefetch]"
+
+// Chapter sanitizes one chapter document: scripts and embeds are
+// stripped, external iframes removed, event handlers and javascript:
+// URLs dropped, and tracking pixels deleted. Parsing through net/html
+// also normalizes malformed markup as a side effect. Input that cannot
+// be parsed at all is returned unchanged.
+func Chapter(content []byte) []byte {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(content))
+	if err != nil {
+		return content
+	}
+
+	doc.Find(removedOutright).Remove()
+
+	// Frames pointing off-site are trackers or players that cannot work
+	// offline; same-book frames (relative src) are kept.
+	doc.Find("iframe").Each(func(_ int, s *goquery.Selection) {
+		src, _ := s.Attr("src")
+		if src == "" || strings.Contains(src, "://") || strings.HasPrefix(src, "//") {
+			s.Remove()
+		}
+	})
+
+	// 1x1 images are analytics pixels.
+	doc.Find("img").Each(func(_ int, s *goquery.Selection) {
+		w, _ := s.Attr("width")
+		h, _ := s.Attr("height")
+		if w == "1" && h == "1" {
+			s.Remove()
+		}
+	})
+
+	// Inline event handlers and javascript: URLs are dead weight at
+	// best; in a terminal-adjacent pipeline they are also a risk.
+	doc.Find("*").Each(func(_ int, s *goquery.Selection) {
+		for _, node := range s.Nodes {
+			kept := node.Attr[:0]
+			for _, attr := range node.Attr {
+				if strings.HasPrefix(strings.ToLower(attr.Key), "on") {
+					continue
+				}
+				if (attr.Key == "href" || attr.Key == "src") &&
+					strings.HasPrefix(strings.ToLower(strings.TrimSpace(attr.Val)), "javascript:") {
+					continue
+				}
+				kept = append(kept, attr)
+			}
+			node.Attr = kept
+		}
+	})
+
+	out, err := doc.Html()
+	if err != nil {
+		return content
+	}
+	return []byte(out)
+}
@@ -0,0 +1,51 @@
+package sanitize
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChapterStripsScriptsAndEmbeds(t *testing.T) {
+	in := `<html><body><p>Keep me</p><script>alert(1)</script><object data="x"></object><embed src="y"></body></html>`
+	out := string(Chapter([]byte(in)))
+	if !strings.Contains(out, "Keep me") {
+		t.Error("content paragraph was lost")
+	}
+	for _, gone := range []string{"<script", "<object", "<embed", "alert(1)"} {
+		if strings.Contains(out, gone) {
+			t.Errorf("output still contains %q: %s", gone, out)
+		}
+	}
+}
+
+func TestChapterRemovesExternalIframesOnly(t *testing.T) {
+	in := `<body><iframe src="https://tracker.example/p"></iframe><iframe src="inline-figure.html"></iframe></body>`
+	out := string(Chapter([]byte(in)))
+	if strings.Contains(out, "tracker.example") {
+		t.Error("external iframe survived")
+	}
+	if !strings.Contains(out, "inline-figure.html") {
+		t.Error("same-book iframe should be kept")
+	}
+}
+
+func TestChapterDropsHandlersAndTrackingPixels(t *testing.T) {
+	in := `<body><a href="javascript:evil()" onclick="evil()">link</a><img src="pixel.gif" width="1" height="1"><img src="figure.png" alt="fig"></body>`
+	out := string(Chapter([]byte(in)))
+	for _, gone := range []string{"javascript:", "onclick", "pixel.gif"} {
+		if strings.Contains(out, gone) {
+			t.Errorf("output still contains %q: %s", gone, out)
+		}
+	}
+	if !strings.Contains(out, "figure.png") {
+		t.Error("real image should be kept")
+	}
+}
+
+func TestChapterNormalizesMalformedMarkup(t *testing.T) {
+	in := `<p>unclosed <b>bold<p>next`
+	out := string(Chapter([]byte(in)))
+	if !strings.Contains(out, "</b>") || !strings.Contains(out, "</p>") {
+		t.Errorf("markup was not normalized: %s", out)
+	}
+}
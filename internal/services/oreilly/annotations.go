@@ -0,0 +1,61 @@
+package oreilly
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Annotation is one highlight the account made on the platform,
+// optionally with an attached note.
+type Annotation struct {
+	ID           string        `json:"identifier"`
+	Quote        string        `json:"quote"`
+	Note         string        `json:"text"`
+	ChapterTitle string        `json:"chapter_title"`
+	ChapterURL   string        `json:"chapter_url"`
+	LastModified string        `json:"last_modified_time"`
+	Book         AnnotatedBook `json:"epub_identifier"`
+}
+
+// AnnotatedBook identifies the work an annotation belongs to.
+type AnnotatedBook struct {
+	ID    string `json:"identifier"`
+	Title string `json:"title"`
+}
+
+// annotationPage is one page of the annotations listing.
+type annotationPage struct {
+	Results []Annotation `json:"results"`
+	Next    string       `json:"next"`
+}
+
+// FetchAnnotations returns every highlight on the account, following
+// the listing's cursor until it runs out.
+func (s *Service) FetchAnnotations(ctx context.Context, jwt string) ([]Annotation, error) {
+	u := apiBase() + "/api/v1/annotations/all/"
+	var out []Annotation
+	for u != "" {
+		resp, err := s.client.Get(ctx, u, s.authHeaders(jwt))
+		if err != nil {
+			return nil, fmt.Errorf("annotations request: %w", err)
+		}
+		var page annotationPage
+		err = func() error {
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				return fmt.Errorf("annotations returned status %d", resp.StatusCode)
+			}
+			return decodeBody(resp.Body, "annotations", &page, "results")
+		}()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, page.Results...)
+		u = page.Next
+		if u != "" && u[0] == '/' {
+			u = apiBase() + u
+		}
+	}
+	return out, nil
+}
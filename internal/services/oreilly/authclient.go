@@ -0,0 +1,110 @@
+package oreilly
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/tuannvm/koreilly/internal/client"
+)
+
+// requester is what a Service needs from its HTTP layer; *client.Client
+// and *AuthenticatedClient both satisfy it.
+type requester interface {
+	Get(ctx context.Context, url string, headers http.Header) (*http.Response, error)
+	Head(ctx context.Context, url string, headers http.Header) (*http.Response, error)
+	Post(ctx context.Context, url string, headers http.Header, body []byte) (*http.Response, error)
+}
+
+// TokenSource supplies the session token for authenticated requests.
+// Refresh is consulted after a 401 and should return a token worth
+// retrying with; returning the same token means "nothing newer exists".
+type TokenSource interface {
+	Token() (string, error)
+	Refresh(ctx context.Context) (string, error)
+}
+
+// AuthenticatedClient wraps a client.Client and attaches the
+// Authorization header and orm-jwt cookie to every request, so service
+// methods no longer thread a raw JWT around. A 401 triggers one token
+// refresh and one retry before the response is handed back.
+type AuthenticatedClient struct {
+	*client.Client
+	source TokenSource
+
+	mu  sync.Mutex
+	jwt string
+}
+
+// NewAuthenticatedClient wraps c with automatic auth from source.
+func NewAuthenticatedClient(c *client.Client, source TokenSource) *AuthenticatedClient {
+	return &AuthenticatedClient{Client: c, source: source}
+}
+
+// token returns the current JWT, fetching it from the source on first
+// use.
+func (a *AuthenticatedClient) token() (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.jwt == "" {
+		jwt, err := a.source.Token()
+		if err != nil {
+			return "", err
+		}
+		a.jwt = jwt
+	}
+	return a.jwt, nil
+}
+
+// withAuth returns headers with the token attached, overriding any
+// stale credentials a caller set by hand.
+func withAuth(headers http.Header, jwt string) http.Header {
+	out := http.Header{}
+	for k, vs := range headers {
+		out[k] = vs
+	}
+	out.Set("Authorization", "Bearer "+jwt)
+	out.Set("Cookie", "orm-jwt="+jwt)
+	return out
+}
+
+// Get issues an authenticated GET, refreshing the token and retrying
+// once if the API answers 401.
+func (a *AuthenticatedClient) Get(ctx context.Context, url string, headers http.Header) (*http.Response, error) {
+	return a.do(ctx, url, headers, a.Client.Get)
+}
+
+// Head issues an authenticated HEAD with the same 401 handling as Get.
+func (a *AuthenticatedClient) Head(ctx context.Context, url string, headers http.Header) (*http.Response, error) {
+	return a.do(ctx, url, headers, a.Client.Head)
+}
+
+// Post issues an authenticated POST with the same 401 handling as Get;
+// the body is replayed on the retry.
+func (a *AuthenticatedClient) Post(ctx context.Context, url string, headers http.Header, body []byte) (*http.Response, error) {
+	return a.do(ctx, url, headers, func(ctx context.Context, u string, h http.Header) (*http.Response, error) {
+		return a.Client.Post(ctx, u, h, body)
+	})
+}
+
+func (a *AuthenticatedClient) do(ctx context.Context, url string, headers http.Header, send func(context.Context, string, http.Header) (*http.Response, error)) (*http.Response, error) {
+	jwt, err := a.token()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := send(ctx, url, withAuth(headers, jwt))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	fresh, refreshErr := a.source.Refresh(ctx)
+	if refreshErr != nil || fresh == "" || fresh == jwt {
+		// Nothing newer to try; the 401 stands.
+		return resp, nil
+	}
+	resp.Body.Close()
+	a.mu.Lock()
+	a.jwt = fresh
+	a.mu.Unlock()
+	return send(ctx, url, withAuth(headers, fresh))
+}
@@ -0,0 +1,74 @@
+package oreilly
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tuannvm/koreilly/internal/client"
+)
+
+type fakeSource struct {
+	current string
+	next    string
+}
+
+func (f *fakeSource) Token() (string, error) { return f.current, nil }
+
+func (f *fakeSource) Refresh(ctx context.Context) (string, error) {
+	if f.next != "" {
+		f.current = f.next
+	}
+	return f.current, nil
+}
+
+func TestAuthenticatedClientRefreshesOn401(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("Authorization") != "Bearer fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	source := &fakeSource{current: "stale", next: "fresh"}
+	a := NewAuthenticatedClient(client.New(client.WithRateLimit(1000, 1000)), source)
+	resp, err := a.Get(context.Background(), srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if requests != 2 {
+		t.Fatalf("server saw %d requests, want 2 (401 then retry)", requests)
+	}
+}
+
+func TestAuthenticatedClientGivesUpWithoutNewerToken(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	source := &fakeSource{current: "stale"}
+	a := NewAuthenticatedClient(client.New(client.WithRateLimit(1000, 1000)), source)
+	resp, err := a.Get(context.Background(), srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401", resp.StatusCode)
+	}
+	if requests != 1 {
+		t.Fatalf("server saw %d requests, want 1 (no retry with the same token)", requests)
+	}
+}
@@ -0,0 +1,90 @@
+package oreilly
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// FetchBookDetail returns the full metadata record for one book.
+func (s *Service) FetchBookDetail(ctx context.Context, jwt, bookID string) (*Book, error) {
+	u := fmt.Sprintf("%s/api/v1/book/%s/", apiBase(), bookID)
+	resp, err := s.client.Get(ctx, u, s.authHeaders(jwt))
+	if err != nil {
+		return nil, fmt.Errorf("book detail request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("book detail returned status %d", resp.StatusCode)
+	}
+	var book Book
+	if err := decodeBody(resp.Body, "book detail", &book, "title", "product_id"); err != nil {
+		return nil, err
+	}
+	if book.ProductID == "" {
+		book.ProductID = bookID
+	}
+	return &book, nil
+}
+
+// FetchRelated returns the titles the platform recommends alongside a
+// book ("readers also viewed").
+func (s *Service) FetchRelated(ctx context.Context, jwt, bookID string) ([]Book, error) {
+	u := fmt.Sprintf("%s/api/v1/book/%s/related/", apiBase(), bookID)
+	resp, err := s.client.Get(ctx, u, s.authHeaders(jwt))
+	if err != nil {
+		return nil, fmt.Errorf("related titles request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("related titles returned status %d", resp.StatusCode)
+	}
+	var page struct {
+		Results []Book `json:"results"`
+	}
+	if err := decodeBody(resp.Body, "related titles", &page, "results"); err != nil {
+		return nil, err
+	}
+	return page.Results, nil
+}
+
+// FetchChapterContent downloads one chapter file of a book's EPUB.
+func (s *Service) FetchChapterContent(ctx context.Context, jwt, bookID, href string) ([]byte, error) {
+	u := fmt.Sprintf("%s/api/v2/epubs/urn:orm:book:%s/files/%s", apiBase(), bookID, href)
+	resp, err := s.client.Get(ctx, u, s.authHeaders(jwt))
+	if err != nil {
+		return nil, fmt.Errorf("chapter request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("chapter %s returned status %d", href, resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// EstimateBookSize HEADs the book's packaged EPUB and returns its size
+// in bytes, or 0 when the API doesn't report one.
+func (s *Service) EstimateBookSize(ctx context.Context, jwt, bookID string) (int64, error) {
+	u := fmt.Sprintf("%s/api/v2/epubs/urn:orm:book:%s", apiBase(), bookID)
+	resp, err := s.client.Head(ctx, u, s.authHeaders(jwt))
+	if err != nil {
+		return 0, fmt.Errorf("size preflight: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, nil
+	}
+	if resp.ContentLength > 0 {
+		return resp.ContentLength, nil
+	}
+	return 0, nil
+}
+
+// authHeaders builds the standard authenticated request headers.
+func (s *Service) authHeaders(jwt string) http.Header {
+	headers := http.Header{}
+	headers.Set("Authorization", "Bearer "+jwt)
+	headers.Set("Cookie", "orm-jwt="+jwt)
+	return headers
+}
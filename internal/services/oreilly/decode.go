@@ -0,0 +1,44 @@
+package oreilly
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+)
+
+// decodeBody decodes an API response leniently: unknown fields are
+// ignored, missing optional fields keep their zero values, and the
+// named top-level fields are probed against the raw payload so a schema
+// change upstream degrades the feature with a warning instead of
+// crashing the command.
+func decodeBody(body io.Reader, endpoint string, dst any, expect ...string) error {
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return fmt.Errorf("reading %s response: %w", endpoint, err)
+	}
+	if err := json.Unmarshal(raw, dst); err != nil {
+		return fmt.Errorf("decoding %s response: %w", endpoint, err)
+	}
+	var probe map[string]json.RawMessage
+	if json.Unmarshal(raw, &probe) == nil {
+		for _, field := range expect {
+			if _, ok := probe[field]; !ok {
+				warnDrift(endpoint, field)
+			}
+		}
+	}
+	return nil
+}
+
+// driftSeen dedupes schema-drift warnings per endpoint and field, so
+// paginated loops don't repeat the same line for every page.
+var driftSeen sync.Map
+
+func warnDrift(endpoint, field string) {
+	if _, loaded := driftSeen.LoadOrStore(endpoint+"\x00"+field, true); loaded {
+		return
+	}
+	slog.Warn("API schema drift: expected field missing", "endpoint", endpoint, "field", field)
+}
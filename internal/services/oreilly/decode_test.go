@@ -0,0 +1,30 @@
+package oreilly
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecodeBodyToleratesUnknownAndMissingFields(t *testing.T) {
+	var page struct {
+		Results []Book `json:"results"`
+		Next    string `json:"next"`
+	}
+	body := `{"results":[{"title":"T","brand_new_field":42}],"extra":true}`
+	if err := decodeBody(strings.NewReader(body), "test", &page, "results", "next"); err != nil {
+		t.Fatalf("decodeBody: %v", err)
+	}
+	if len(page.Results) != 1 || page.Results[0].Title != "T" {
+		t.Errorf("results = %+v", page.Results)
+	}
+	if page.Next != "" {
+		t.Errorf("missing field should default, got %q", page.Next)
+	}
+}
+
+func TestDecodeBodyRejectsMalformedJSON(t *testing.T) {
+	var out struct{}
+	if err := decodeBody(strings.NewReader("<html>"), "test", &out); err == nil {
+		t.Fatal("expected an error for non-JSON body")
+	}
+}
@@ -0,0 +1,38 @@
+package oreilly
+
+// Book is a single work (book, video, etc.) on the O'Reilly platform as
+// returned by the search and metadata APIs.
+type Book struct {
+	ID         string   `json:"archive_id"`
+	ProductID  string   `json:"product_id"`
+	Title      string   `json:"title"`
+	Authors    []string `json:"authors"`
+	ISBN       string   `json:"isbn"`
+	Publishers []string `json:"publishers"`
+	Format     string   `json:"format"`
+	Issued     string   `json:"issued"`
+	// Level is the platform's difficulty rating (beginner,
+	// intermediate, advanced); many titles leave it empty.
+	Level string `json:"level,omitempty"`
+	// AverageRating is the 0–5 reader score; PopularityRank orders the
+	// catalog by usage, 1 being the most popular. Zero means unrated.
+	AverageRating  float64 `json:"average_rating,omitempty"`
+	PopularityRank int     `json:"popularity_rank,omitempty"`
+	WebURL         string  `json:"web_url"`
+	CoverURL       string  `json:"cover_url"`
+	Description    string  `json:"description"`
+
+	// Only the detail endpoint fills these in; search hits leave them
+	// zero.
+	VirtualPages int      `json:"virtual_pages"`
+	Duration     float64  `json:"duration_seconds"`
+	Topics       []string `json:"topics"`
+}
+
+// SearchResult is one page of search hits.
+type SearchResult struct {
+	Total    int    `json:"total"`
+	Results  []Book `json:"results"`
+	Next     string `json:"next"`
+	Previous string `json:"previous"`
+}
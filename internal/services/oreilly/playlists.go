@@ -0,0 +1,81 @@
+package oreilly
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// PlaylistItem is one work referenced by a playlist. Playlists mix
+// formats, so downloads filter on Format.
+type PlaylistItem struct {
+	ProductID string `json:"product_id"`
+	Title     string `json:"title"`
+	Format    string `json:"content_format"`
+}
+
+// Playlist is one collection on the platform, either owned by the user
+// or followed. The list endpoint leaves Content empty; FetchPlaylist
+// fills it in.
+type Playlist struct {
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	IsOwned     bool           `json:"is_owned"`
+	Following   bool           `json:"is_following"`
+	Content     []PlaylistItem `json:"content"`
+}
+
+// playlistPage is one page of the playlists listing.
+type playlistPage struct {
+	Results []Playlist `json:"results"`
+	Next    string     `json:"next"`
+}
+
+// ListPlaylists returns the user's own and followed playlists, following
+// pagination cursors until exhausted.
+func (s *Service) ListPlaylists(ctx context.Context, jwt string) ([]Playlist, error) {
+	u := apiBase() + "/api/v2/playlists/"
+	var all []Playlist
+	for u != "" {
+		resp, err := s.client.Get(ctx, u, s.authHeaders(jwt))
+		if err != nil {
+			return nil, fmt.Errorf("playlists request: %w", err)
+		}
+		var page playlistPage
+		err = func() error {
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				return fmt.Errorf("playlists returned status %d", resp.StatusCode)
+			}
+			return decodeBody(resp.Body, "playlists", &page, "results")
+		}()
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page.Results...)
+		u = page.Next
+		if u != "" && u[0] == '/' {
+			u = apiBase() + u
+		}
+	}
+	return all, nil
+}
+
+// FetchPlaylist returns one playlist with its content list populated.
+func (s *Service) FetchPlaylist(ctx context.Context, jwt, id string) (*Playlist, error) {
+	u := fmt.Sprintf("%s/api/v2/playlists/%s/", apiBase(), id)
+	resp, err := s.client.Get(ctx, u, s.authHeaders(jwt))
+	if err != nil {
+		return nil, fmt.Errorf("playlist request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("playlist %s returned status %d", id, resp.StatusCode)
+	}
+	var p Playlist
+	if err := decodeBody(resp.Body, "playlist", &p, "name", "content"); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
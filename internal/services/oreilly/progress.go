@@ -0,0 +1,72 @@
+package oreilly
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// BookProgress is the platform's reading position for one work, as a
+// fraction of the whole.
+type BookProgress struct {
+	BookID  string  `json:"book_id"`
+	Percent float64 `json:"percent_complete"`
+}
+
+// progressPage is one page of the progress listing.
+type progressPage struct {
+	Results []BookProgress `json:"results"`
+	Next    string         `json:"next"`
+}
+
+// FetchProgress returns the platform-side reading progress for every
+// title the account has opened, keyed by product id.
+func (s *Service) FetchProgress(ctx context.Context, jwt string) (map[string]float64, error) {
+	u := apiBase() + "/api/v1/progress/"
+	out := map[string]float64{}
+	for u != "" {
+		resp, err := s.client.Get(ctx, u, s.authHeaders(jwt))
+		if err != nil {
+			return nil, fmt.Errorf("progress request: %w", err)
+		}
+		var page progressPage
+		err = func() error {
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				return fmt.Errorf("progress returned status %d", resp.StatusCode)
+			}
+			return decodeBody(resp.Body, "progress", &page, "results")
+		}()
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range page.Results {
+			out[p.BookID] = p.Percent
+		}
+		u = page.Next
+		if u != "" && u[0] == '/' {
+			u = apiBase() + u
+		}
+	}
+	return out, nil
+}
+
+// PostProgress reports a local reading position back to the platform,
+// so the website resumes where the terminal reader stopped.
+func (s *Service) PostProgress(ctx context.Context, jwt, bookID string, percent float64) error {
+	body, err := json.Marshal(BookProgress{BookID: bookID, Percent: percent})
+	if err != nil {
+		return err
+	}
+	u := fmt.Sprintf("%s/api/v1/progress/%s/", apiBase(), bookID)
+	resp, err := s.client.Post(ctx, u, s.authHeaders(jwt), body)
+	if err != nil {
+		return fmt.Errorf("posting progress: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("progress update returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+// Package oreilly implements the calls goreilly makes against the
+// learning.oreilly.com API: search, metadata, TOC, and content fetching.
+package oreilly
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tuannvm/koreilly/internal/client"
+	"github.com/tuannvm/koreilly/internal/config"
+)
+
+const defaultBaseURL = "https://learning.oreilly.com"
+
+// apiBase returns the API origin, honoring GOREILLY_BASE_URL so a mock
+// server (cmd/mockoreilly) can stand in for the real platform.
+func apiBase() string {
+	if v := os.Getenv("GOREILLY_BASE_URL"); v != "" {
+		return strings.TrimSuffix(v, "/")
+	}
+	return defaultBaseURL
+}
+
+// Service exposes the O'Reilly API operations.
+type Service struct {
+	client requester
+}
+
+// NewService builds a Service with its own HTTP client, sharing the
+// persistent cookie jar with the rest of the program.
+func NewService(opts ...client.Option) *Service {
+	return &Service{client: newBaseClient(opts...)}
+}
+
+// NewAuthenticatedService builds a Service whose requests carry the
+// source's token automatically, including the 401-refresh-retry cycle;
+// the jwt arguments on its methods are then only a fallback.
+func NewAuthenticatedService(source TokenSource, opts ...client.Option) *Service {
+	return &Service{client: NewAuthenticatedClient(newBaseClient(opts...), source)}
+}
+
+func newBaseClient(opts ...client.Option) *client.Client {
+	hc := &http.Client{
+		Timeout: 30 * time.Second,
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+		},
+	}
+	// An unusable jar just means cookies don't persist this run.
+	if jar, err := client.NewPersistentJar(config.CookiesPath()); err == nil {
+		hc.Jar = jar
+	}
+	opts = append([]client.Option{client.WithHTTPClient(hc)}, opts...)
+	return client.New(opts...)
+}
+
+// SearchOptions narrows a search by the API's facet parameters. Zero
+// values mean "no filter".
+type SearchOptions struct {
+	Topics      []string
+	Languages   []string
+	Publishers  []string
+	Formats     []string // book, video, audiobook, ...
+	Levels      []string // beginner, intermediate, advanced
+	IssuedAfter time.Time
+	// Page selects a specific result page (1-based); zero or one means
+	// the first page.
+	Page int
+}
+
+// query encodes the options into API facet parameters.
+func (o SearchOptions) query(v url.Values) {
+	for _, t := range o.Topics {
+		v.Add("topics", t)
+	}
+	for _, l := range o.Languages {
+		v.Add("languages", l)
+	}
+	for _, p := range o.Publishers {
+		v.Add("publishers", p)
+	}
+	for _, f := range o.Formats {
+		v.Add("formats", f)
+	}
+	for _, l := range o.Levels {
+		v.Add("levels", l)
+	}
+	if !o.IssuedAfter.IsZero() {
+		v.Set("issued_after", o.IssuedAfter.Format("2006-01-02"))
+	}
+}
+
+// SearchBooks queries the v2 search API with the given free-text query
+// and facet filters, returning the first page of results.
+func (s *Service) SearchBooks(ctx context.Context, jwt, query string, opts SearchOptions) (*SearchResult, error) {
+	v := url.Values{}
+	v.Set("query", query)
+	opts.query(v)
+	if opts.Page > 1 {
+		v.Set("page", fmt.Sprint(opts.Page))
+	}
+	return s.fetchSearchPage(ctx, jwt, apiBase()+"/api/v2/search/?"+v.Encode())
+}
+
+// SearchBooksPage follows a cursor URL from SearchResult.Next (or
+// Previous) and returns that page.
+func (s *Service) SearchBooksPage(ctx context.Context, jwt, cursor string) (*SearchResult, error) {
+	u := cursor
+	if !strings.HasPrefix(u, "http") {
+		u = apiBase() + u
+	}
+	return s.fetchSearchPage(ctx, jwt, u)
+}
+
+// SearchBooksAll follows Next cursors until the result set is exhausted
+// or maxPages is reached (0 means no limit).
+func (s *Service) SearchBooksAll(ctx context.Context, jwt, query string, opts SearchOptions, maxPages int) (*SearchResult, error) {
+	result, err := s.SearchBooks(ctx, jwt, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	for pages := 1; result.Next != "" && (maxPages == 0 || pages < maxPages); pages++ {
+		page, err := s.SearchBooksPage(ctx, jwt, result.Next)
+		if err != nil {
+			return nil, fmt.Errorf("following search cursor: %w", err)
+		}
+		result.Results = append(result.Results, page.Results...)
+		result.Next = page.Next
+	}
+	return result, nil
+}
+
+func (s *Service) fetchSearchPage(ctx context.Context, jwt, u string) (*SearchResult, error) {
+	headers := http.Header{}
+	headers.Set("Authorization", "Bearer "+jwt)
+	headers.Set("Cookie", "orm-jwt="+jwt)
+
+	resp, err := s.client.Get(ctx, u, headers)
+	if err != nil {
+		return nil, fmt.Errorf("search request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("search returned status %d", resp.StatusCode)
+	}
+
+	var result SearchResult
+	if err := decodeBody(resp.Body, "search", &result, "results", "total"); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,50 @@
+package oreilly_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/tuannvm/koreilly/internal/oreillytest"
+	"github.com/tuannvm/koreilly/internal/services/oreilly"
+)
+
+func TestServiceAgainstFixtureServer(t *testing.T) {
+	oreillytest.NewServer(t)
+	svc := oreilly.NewService()
+	ctx := context.Background()
+
+	result, err := svc.SearchBooks(ctx, "jwt", "patterns", oreilly.SearchOptions{})
+	if err != nil {
+		t.Fatalf("SearchBooks: %v", err)
+	}
+	if len(result.Results) != 1 || result.Results[0].Title != "Mock Go Patterns" {
+		t.Fatalf("unexpected search results: %+v", result.Results)
+	}
+
+	id := result.Results[0].ProductID
+	book, err := svc.FetchBookDetail(ctx, "jwt", id)
+	if err != nil {
+		t.Fatalf("FetchBookDetail: %v", err)
+	}
+	if book.ISBN != id {
+		t.Errorf("detail ISBN = %q, want %q", book.ISBN, id)
+	}
+
+	toc, err := svc.FetchTOC(ctx, "jwt", id)
+	if err != nil {
+		t.Fatalf("FetchTOC: %v", err)
+	}
+	flat := oreilly.FlattenChapters(toc)
+	if len(flat) != len(oreillytest.ChapterTitles) {
+		t.Fatalf("got %d chapters, want %d", len(flat), len(oreillytest.ChapterTitles))
+	}
+
+	content, err := svc.FetchChapterContent(ctx, "jwt", id, flat[0].Href)
+	if err != nil {
+		t.Fatalf("FetchChapterContent: %v", err)
+	}
+	if !strings.Contains(string(content), oreillytest.ChapterTitles[0]) {
+		t.Errorf("chapter content missing title: %s", content)
+	}
+}
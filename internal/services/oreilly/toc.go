@@ -0,0 +1,197 @@
+package oreilly
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Chapter is a TOC entry of a book. Entries form a tree mirroring the
+// nesting of the navigation document.
+type Chapter struct {
+	Title    string    `json:"title"`
+	Href     string    `json:"href"`
+	Fragment string    `json:"fragment,omitempty"`
+	Order    int       `json:"order"`
+	Depth    int       `json:"depth"`
+	Words    int       `json:"words,omitempty"`
+	Children []Chapter `json:"children,omitempty"`
+}
+
+// FlattenChapters returns the tree in reading order as a flat list.
+func FlattenChapters(chapters []Chapter) []Chapter {
+	var flat []Chapter
+	for _, ch := range chapters {
+		children := ch.Children
+		ch.Children = nil
+		flat = append(flat, ch)
+		flat = append(flat, FlattenChapters(children)...)
+	}
+	return flat
+}
+
+// readingWPM is the assumed reading speed for time estimates.
+const readingWPM = 230
+
+// WordCount counts the words of a chapter's rendered text, ignoring
+// markup.
+func WordCount(content []byte) int {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(content))
+	if err != nil {
+		return len(strings.Fields(string(content)))
+	}
+	return len(strings.Fields(doc.Text()))
+}
+
+// ReadingMinutes estimates how long a chapter of the given word count
+// takes to read, rounding up so short chapters never show zero.
+func ReadingMinutes(words int) int {
+	if words <= 0 {
+		return 0
+	}
+	return (words + readingWPM - 1) / readingWPM
+}
+
+// FetchTOC downloads a book's navigation document and parses it into a
+// chapter tree. It prefers the EPUB nav document and falls back to the
+// legacy NCX when the nav is unavailable.
+func (s *Service) FetchTOC(ctx context.Context, jwt, bookID string) ([]Chapter, error) {
+	headers := http.Header{}
+	headers.Set("Authorization", "Bearer "+jwt)
+	headers.Set("Cookie", "orm-jwt="+jwt)
+
+	navURL := fmt.Sprintf("%s/api/v2/epubs/urn:orm:book:%s/files/navigation.xhtml", apiBase(), bookID)
+	resp, err := s.client.Get(ctx, navURL, headers)
+	if err != nil {
+		return nil, fmt.Errorf("toc request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		chapters, err := ParseNavTOC(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("parsing nav document: %w", err)
+		}
+		return chapters, nil
+	}
+
+	// Legacy titles ship only an NCX.
+	ncxURL := fmt.Sprintf("%s/api/v2/epubs/urn:orm:book:%s/files/toc.ncx", apiBase(), bookID)
+	resp2, err := s.client.Get(ctx, ncxURL, headers)
+	if err != nil {
+		return nil, fmt.Errorf("ncx request: %w", err)
+	}
+	defer resp2.Body.Close()
+	if resp2.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("toc returned status %d (nav) and %d (ncx)", resp.StatusCode, resp2.StatusCode)
+	}
+	chapters, err := ParseNCXTOC(resp2.Body)
+	if err != nil {
+		return nil, fmt.Errorf("parsing ncx document: %w", err)
+	}
+	return chapters, nil
+}
+
+// ParseNavTOC parses an EPUB 3 navigation document, following the
+// nested <ol> structure into a chapter tree.
+func ParseNavTOC(r io.Reader) ([]Chapter, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, err
+	}
+	// Prefer the toc nav; fall back to the first nav or top-level ol.
+	root := doc.Find(`nav[epub\:type="toc"] > ol`).First()
+	if root.Length() == 0 {
+		root = doc.Find("nav > ol").First()
+	}
+	if root.Length() == 0 {
+		root = doc.Find("ol").First()
+	}
+	if root.Length() == 0 {
+		return nil, fmt.Errorf("no <ol> list found in navigation document")
+	}
+	order := 0
+	return parseNavList(root, 0, &order), nil
+}
+
+func parseNavList(list *goquery.Selection, depth int, order *int) []Chapter {
+	var chapters []Chapter
+	list.ChildrenFiltered("li").Each(func(_ int, li *goquery.Selection) {
+		a := li.ChildrenFiltered("a").First()
+		if a.Length() == 0 {
+			a = li.Find("a").First()
+		}
+		if a.Length() == 0 {
+			return
+		}
+		*order++
+		href, _ := a.Attr("href")
+		ch := Chapter{
+			Title: strings.TrimSpace(a.Text()),
+			Order: *order,
+			Depth: depth,
+		}
+		ch.Href, ch.Fragment = splitFragment(href)
+		if sub := li.ChildrenFiltered("ol").First(); sub.Length() > 0 {
+			ch.Children = parseNavList(sub, depth+1, order)
+		}
+		chapters = append(chapters, ch)
+	})
+	return chapters
+}
+
+// ncx mirrors the subset of the NCX schema we need.
+type ncx struct {
+	NavMap struct {
+		NavPoints []ncxNavPoint `xml:"navPoint"`
+	} `xml:"navMap"`
+}
+
+type ncxNavPoint struct {
+	Label struct {
+		Text string `xml:"text"`
+	} `xml:"navLabel"`
+	Content struct {
+		Src string `xml:"src,attr"`
+	} `xml:"content"`
+	Children []ncxNavPoint `xml:"navPoint"`
+}
+
+// ParseNCXTOC parses a legacy NCX table of contents into a chapter tree.
+func ParseNCXTOC(r io.Reader) ([]Chapter, error) {
+	var doc ncx
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+	order := 0
+	return parseNavPoints(doc.NavMap.NavPoints, 0, &order), nil
+}
+
+func parseNavPoints(points []ncxNavPoint, depth int, order *int) []Chapter {
+	var chapters []Chapter
+	for _, p := range points {
+		*order++
+		ch := Chapter{
+			Title: strings.TrimSpace(p.Label.Text),
+			Order: *order,
+			Depth: depth,
+		}
+		ch.Href, ch.Fragment = splitFragment(p.Content.Src)
+		ch.Children = parseNavPoints(p.Children, depth+1, order)
+		chapters = append(chapters, ch)
+	}
+	return chapters
+}
+
+// splitFragment separates a href into its path and #fragment anchor.
+func splitFragment(href string) (path, fragment string) {
+	if i := strings.IndexByte(href, '#'); i >= 0 {
+		return href[:i], href[i+1:]
+	}
+	return href, ""
+}
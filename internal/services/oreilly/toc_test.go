@@ -0,0 +1,81 @@
+package oreilly
+
+import (
+	"strings"
+	"testing"
+)
+
+const navDoc = `<?xml version="1.0" encoding="utf-8"?>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops">
+<body>
+<nav epub:type="toc">
+<ol>
+  <li><a href="preface.xhtml">Preface</a></li>
+  <li><a href="ch01.xhtml">Chapter 1</a>
+    <ol>
+      <li><a href="ch01.xhtml#sec1">Section 1.1</a></li>
+      <li><a href="ch01.xhtml#sec2">Section 1.2</a></li>
+    </ol>
+  </li>
+  <li><a href="ch02.xhtml">Chapter 2</a></li>
+</ol>
+</nav>
+</body>
+</html>`
+
+const ncxDoc = `<?xml version="1.0" encoding="utf-8"?>
+<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1">
+<navMap>
+  <navPoint id="p1"><navLabel><text>Preface</text></navLabel><content src="preface.html"/></navPoint>
+  <navPoint id="p2"><navLabel><text>Chapter 1</text></navLabel><content src="ch01.html"/>
+    <navPoint id="p3"><navLabel><text>Section 1.1</text></navLabel><content src="ch01.html#sec1"/></navPoint>
+  </navPoint>
+</navMap>
+</ncx>`
+
+func TestParseNavTOC(t *testing.T) {
+	chapters, err := ParseNavTOC(strings.NewReader(navDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chapters) != 3 {
+		t.Fatalf("got %d top-level chapters, want 3", len(chapters))
+	}
+	ch1 := chapters[1]
+	if ch1.Title != "Chapter 1" || ch1.Depth != 0 {
+		t.Errorf("unexpected chapter: %+v", ch1)
+	}
+	if len(ch1.Children) != 2 {
+		t.Fatalf("got %d children, want 2", len(ch1.Children))
+	}
+	sec := ch1.Children[0]
+	if sec.Href != "ch01.xhtml" || sec.Fragment != "sec1" || sec.Depth != 1 {
+		t.Errorf("unexpected section: %+v", sec)
+	}
+	flat := FlattenChapters(chapters)
+	if len(flat) != 5 {
+		t.Errorf("flattened to %d entries, want 5", len(flat))
+	}
+	for i, ch := range flat {
+		if ch.Order != i+1 {
+			t.Errorf("entry %d has order %d", i, ch.Order)
+		}
+	}
+}
+
+func TestParseNCXTOC(t *testing.T) {
+	chapters, err := ParseNCXTOC(strings.NewReader(ncxDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chapters) != 2 {
+		t.Fatalf("got %d top-level chapters, want 2", len(chapters))
+	}
+	if len(chapters[1].Children) != 1 {
+		t.Fatalf("got %d children, want 1", len(chapters[1].Children))
+	}
+	sec := chapters[1].Children[0]
+	if sec.Title != "Section 1.1" || sec.Fragment != "sec1" || sec.Depth != 1 {
+		t.Errorf("unexpected section: %+v", sec)
+	}
+}
@@ -0,0 +1,49 @@
+package oreilly
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ResolveID turns a learning.oreilly.com link into the content ID it
+// refers to, so commands accept pasted URLs anywhere they accept an ID.
+// Anything that doesn't look like a URL is returned unchanged.
+func ResolveID(arg string) (string, error) {
+	if !strings.Contains(arg, "://") {
+		return arg, nil
+	}
+	u, err := url.Parse(arg)
+	if err != nil {
+		return "", fmt.Errorf("parsing URL %q: %w", arg, err)
+	}
+	host := strings.TrimPrefix(u.Hostname(), "www.")
+	if host != "learning.oreilly.com" && host != "oreilly.com" {
+		return "", fmt.Errorf("%s is not an O'Reilly learning URL", arg)
+	}
+
+	segments := strings.FieldsFunc(u.Path, func(r rune) bool { return r == '/' })
+	for i, seg := range segments {
+		switch seg {
+		case "view":
+			// /library/view/<slug>/<id>/... and /videos/<slug>/<id>/...
+			if i+2 < len(segments) {
+				return segments[i+2], nil
+			}
+		case "book", "videos":
+			// /api/v1/book/<id>/ — but /videos/<slug>/<id> is handled
+			// below since the slug comes first there.
+			if seg == "book" && i+1 < len(segments) {
+				return segments[i+1], nil
+			}
+			if seg == "videos" && i+2 < len(segments) {
+				return segments[i+2], nil
+			}
+		}
+		// /api/v2/epubs/urn:orm:book:<id>/...
+		if id, ok := strings.CutPrefix(seg, "urn:orm:book:"); ok {
+			return id, nil
+		}
+	}
+	return "", fmt.Errorf("could not find a book or course ID in %s", arg)
+}
@@ -0,0 +1,39 @@
+package oreilly
+
+import "testing"
+
+func TestResolveID(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{"9781492077213", "9781492077213"},
+		{"https://learning.oreilly.com/library/view/learning-go/9781492077213/", "9781492077213"},
+		{"https://learning.oreilly.com/library/view/learning-go/9781492077213/ch04.html", "9781492077213"},
+		{"https://www.oreilly.com/library/view/learning-go/9781492077213/", "9781492077213"},
+		{"https://learning.oreilly.com/api/v1/book/9781492077213/", "9781492077213"},
+		{"https://learning.oreilly.com/api/v2/epubs/urn:orm:book:9781492077213/files/nav.xhtml", "9781492077213"},
+		{"https://learning.oreilly.com/videos/kubernetes-fast-track/0636920461616/", "0636920461616"},
+	}
+	for _, c := range cases {
+		got, err := ResolveID(c.in)
+		if err != nil {
+			t.Errorf("ResolveID(%q): %v", c.in, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("ResolveID(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestResolveIDRejectsForeignURLs(t *testing.T) {
+	for _, in := range []string{
+		"https://example.com/library/view/foo/123/",
+		"https://learning.oreilly.com/home/",
+	} {
+		if id, err := ResolveID(in); err == nil {
+			t.Errorf("ResolveID(%q) = %q, want error", in, id)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package oreilly
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/tuannvm/koreilly/internal/util"
+)
+
+// VideoLesson is one lesson of a video course.
+type VideoLesson struct {
+	Section     string `json:"section"`
+	SectionNum  int    `json:"section_num"`
+	LessonNum   int    `json:"lesson_num"`
+	Title       string `json:"title"`
+	MediaURL    string `json:"media_url"`    // MP4 or HLS (.m3u8) URL
+	CaptionsURL string `json:"captions_url"` // VTT captions, optional
+}
+
+// ListVideoLessons returns the lessons of a video course in order.
+func (s *Service) ListVideoLessons(ctx context.Context, jwt, courseID string) ([]VideoLesson, error) {
+	u := fmt.Sprintf("%s/api/v1/videos/%s/toc/", apiBase(), courseID)
+	resp, err := s.client.Get(ctx, u, s.authHeaders(jwt))
+	if err != nil {
+		return nil, fmt.Errorf("video toc request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("video toc returned status %d", resp.StatusCode)
+	}
+	var payload struct {
+		Sections []struct {
+			Title   string `json:"title"`
+			Lessons []struct {
+				Title       string `json:"title"`
+				MediaURL    string `json:"media_url"`
+				CaptionsURL string `json:"captions_url"`
+			} `json:"lessons"`
+		} `json:"sections"`
+	}
+	if err := decodeBody(resp.Body, "video toc", &payload, "sections"); err != nil {
+		return nil, err
+	}
+	var lessons []VideoLesson
+	for si, sec := range payload.Sections {
+		for li, l := range sec.Lessons {
+			lessons = append(lessons, VideoLesson{
+				Section:     sec.Title,
+				SectionNum:  si + 1,
+				LessonNum:   li + 1,
+				Title:       l.Title,
+				MediaURL:    l.MediaURL,
+				CaptionsURL: l.CaptionsURL,
+			})
+		}
+	}
+	return lessons, nil
+}
+
+// DownloadVideoLesson saves one lesson (and its captions, if any) into
+// dir, named by section/lesson number. HLS playlists are downloaded
+// segment by segment and remuxed to MP4 with ffmpeg when available,
+// otherwise kept as a concatenated .ts file.
+func (s *Service) DownloadVideoLesson(ctx context.Context, jwt string, lesson VideoLesson, dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("creating video dir: %w", err)
+	}
+	base := fmt.Sprintf("%02d-%02d %s", lesson.SectionNum, lesson.LessonNum, util.SanitizeFilename(lesson.Title))
+
+	var out string
+	var err error
+	if strings.Contains(lesson.MediaURL, ".m3u8") {
+		out, err = s.downloadHLS(ctx, jwt, lesson.MediaURL, filepath.Join(dir, base))
+	} else {
+		out = filepath.Join(dir, base+".mp4")
+		err = s.downloadToFile(ctx, jwt, lesson.MediaURL, out)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if lesson.CaptionsURL != "" {
+		if capErr := s.downloadToFile(ctx, jwt, lesson.CaptionsURL, filepath.Join(dir, base+".vtt")); capErr != nil {
+			return out, fmt.Errorf("video saved but captions failed: %w", capErr)
+		}
+	}
+	return out, nil
+}
+
+// downloadHLS fetches a media playlist and concatenates its TS segments,
+// remuxing to MP4 via ffmpeg when it is on PATH.
+func (s *Service) downloadHLS(ctx context.Context, jwt, playlistURL, base string) (string, error) {
+	resp, err := s.client.Get(ctx, playlistURL, s.authHeaders(jwt))
+	if err != nil {
+		return "", fmt.Errorf("playlist request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("playlist returned status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	// media_url commonly points at a master playlist whose entries are
+	// variant playlists, not segments; concatenating those URIs would
+	// produce a corrupt file. Recurse into the best variant instead.
+	if strings.Contains(string(body), "#EXT-X-STREAM-INF") {
+		variant := pickVariant(string(body))
+		if variant == "" {
+			return "", fmt.Errorf("master playlist %s lists no variants", playlistURL)
+		}
+		variantURL, err := resolveSegmentURL(playlistURL, variant)
+		if err != nil {
+			return "", err
+		}
+		if variantURL == playlistURL {
+			return "", fmt.Errorf("master playlist %s points at itself", playlistURL)
+		}
+		return s.downloadHLS(ctx, jwt, variantURL, base)
+	}
+
+	tsPath := base + ".ts"
+	f, err := os.Create(tsPath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	for _, line := range strings.Split(string(body), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		segURL, err := resolveSegmentURL(playlistURL, line)
+		if err != nil {
+			return "", err
+		}
+		segResp, err := s.client.Get(ctx, segURL, s.authHeaders(jwt))
+		if err != nil {
+			return "", fmt.Errorf("segment %s: %w", line, err)
+		}
+		_, err = io.Copy(f, segResp.Body)
+		segResp.Body.Close()
+		if err != nil {
+			return "", fmt.Errorf("writing segment %s: %w", line, err)
+		}
+	}
+
+	// Remux to MP4 when ffmpeg is available; the raw TS is still playable.
+	if ffmpeg, err := exec.LookPath("ffmpeg"); err == nil {
+		mp4Path := base + ".mp4"
+		cmd := exec.CommandContext(ctx, ffmpeg, "-y", "-i", tsPath, "-c", "copy", mp4Path)
+		if err := cmd.Run(); err == nil {
+			os.Remove(tsPath)
+			return mp4Path, nil
+		}
+	}
+	return tsPath, nil
+}
+
+// FetchCaptions returns a lesson's caption document (WebVTT).
+func (s *Service) FetchCaptions(ctx context.Context, jwt, captionsURL string) ([]byte, error) {
+	resp, err := s.client.Get(ctx, captionsURL, s.authHeaders(jwt))
+	if err != nil {
+		return nil, fmt.Errorf("captions request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("captions returned status %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func (s *Service) downloadToFile(ctx context.Context, jwt, rawURL, path string) error {
+	resp, err := s.client.Get(ctx, rawURL, s.authHeaders(jwt))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", rawURL, resp.StatusCode)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(f, resp.Body)
+	return err
+}
+
+// pickVariant returns the URI of the highest-bandwidth variant in an
+// HLS master playlist, or "" when none is listed. Variants without a
+// BANDWIDTH attribute rank below any that declare one.
+func pickVariant(playlist string) string {
+	var best string
+	bestBW := -1
+	bw := -1
+	pending := false
+	for _, line := range strings.Split(playlist, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "#EXT-X-STREAM-INF"):
+			pending = true
+			bw = 0
+			if i := strings.Index(line, "BANDWIDTH="); i >= 0 {
+				rest := line[i+len("BANDWIDTH="):]
+				if j := strings.IndexByte(rest, ','); j >= 0 {
+					rest = rest[:j]
+				}
+				if v, err := strconv.Atoi(rest); err == nil {
+					bw = v
+				}
+			}
+		case line == "" || strings.HasPrefix(line, "#"):
+			// Other tags between the stream info and its URI are ignored.
+		case pending:
+			if bw > bestBW {
+				best, bestBW = line, bw
+			}
+			pending = false
+		}
+	}
+	return best
+}
+
+// resolveSegmentURL resolves a (possibly relative) segment URI against
+// the playlist URL.
+func resolveSegmentURL(playlistURL, segment string) (string, error) {
+	base, err := url.Parse(playlistURL)
+	if err != nil {
+		return "", err
+	}
+	ref, err := url.Parse(segment)
+	if err != nil {
+		return "", err
+	}
+	return base.ResolveReference(ref).String(), nil
+}
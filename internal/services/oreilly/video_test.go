@@ -0,0 +1,29 @@
+package oreilly
+
+import "testing"
+
+func TestPickVariantChoosesHighestBandwidth(t *testing.T) {
+	playlist := `#EXTM3U
+#EXT-X-STREAM-INF:BANDWIDTH=800000,RESOLUTION=640x360
+low/index.m3u8
+#EXT-X-STREAM-INF:BANDWIDTH=2500000,RESOLUTION=1280x720
+hd/index.m3u8
+#EXT-X-STREAM-INF:BANDWIDTH=1200000
+mid/index.m3u8
+`
+	if got := pickVariant(playlist); got != "hd/index.m3u8" {
+		t.Errorf("pickVariant = %q, want hd/index.m3u8", got)
+	}
+}
+
+func TestPickVariantEmptyForMediaPlaylist(t *testing.T) {
+	playlist := `#EXTM3U
+#EXTINF:6.0,
+segment0.ts
+#EXTINF:6.0,
+segment1.ts
+`
+	if got := pickVariant(playlist); got != "" {
+		t.Errorf("pickVariant = %q, want empty", got)
+	}
+}
@@ -0,0 +1,82 @@
+package stats
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Bucket is one time-bucketed aggregate row of the usage series.
+type Bucket struct {
+	Start          time.Time `json:"start"`
+	Downloads      int       `json:"downloads"`
+	Bytes          int64     `json:"bytes"`
+	ReadingMinutes float64   `json:"reading_minutes"`
+}
+
+// Series aggregates all events since the given time into buckets of the
+// given width (e.g. time.Hour, 24*time.Hour), sorted chronologically.
+func (s *Store) Series(since time.Time, width time.Duration) ([]Bucket, error) {
+	events, err := s.All()
+	if err != nil {
+		return nil, err
+	}
+	byStart := make(map[time.Time]*Bucket)
+	for _, e := range events {
+		if e.Time.Before(since) {
+			continue
+		}
+		start := e.Time.Truncate(width)
+		b, ok := byStart[start]
+		if !ok {
+			b = &Bucket{Start: start}
+			byStart[start] = b
+		}
+		switch e.Type {
+		case EventDownload:
+			b.Downloads++
+			b.Bytes += e.Bytes
+		case EventReading:
+			b.ReadingMinutes += e.Minutes
+		}
+	}
+	buckets := make([]Bucket, 0, len(byStart))
+	for _, b := range byStart {
+		buckets = append(buckets, *b)
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].Start.Before(buckets[j].Start) })
+	return buckets, nil
+}
+
+// WriteCSV renders the series as CSV with an RFC 3339 time column,
+// ready for Grafana's CSV datasource.
+func WriteCSV(w io.Writer, buckets []Bucket) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"time", "downloads", "bytes", "reading_minutes"}); err != nil {
+		return err
+	}
+	for _, b := range buckets {
+		row := []string{
+			b.Start.Format(time.RFC3339),
+			strconv.Itoa(b.Downloads),
+			strconv.FormatInt(b.Bytes, 10),
+			fmt.Sprintf("%.1f", b.ReadingMinutes),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON renders the series as a JSON array.
+func WriteJSON(w io.Writer, buckets []Bucket) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(buckets)
+}
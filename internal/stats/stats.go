@@ -0,0 +1,103 @@
+// Package stats records usage events (downloads, reading sessions) in a
+// local append-only store and aggregates them for the stats command.
+package stats
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tuannvm/koreilly/internal/config"
+)
+
+// Event types recorded in the store.
+const (
+	EventDownload = "download"
+	EventReading  = "reading"
+)
+
+// Event is a single usage record.
+type Event struct {
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"`
+	BookID  string    `json:"book_id"`
+	Title   string    `json:"title,omitempty"`
+	Bytes   int64     `json:"bytes,omitempty"`
+	Minutes float64   `json:"minutes,omitempty"`
+}
+
+// Store is an append-only JSON-lines event log.
+type Store struct {
+	path string
+}
+
+// NewStore returns the store backed by events.jsonl in the config dir.
+func NewStore() *Store {
+	return &Store{path: filepath.Join(config.DataDir(), "events.jsonl")}
+}
+
+// Append writes one event to the log.
+func (s *Store) Append(e Event) error {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("creating stats dir: %w", err)
+	}
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening stats log: %w", err)
+	}
+	defer f.Close()
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// All returns every recorded event in log order.
+func (s *Store) All() ([]Event, error) {
+	f, err := os.Open(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening stats log: %w", err)
+	}
+	defer f.Close()
+	var events []Event
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var e Event
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			continue // skip corrupt lines rather than fail the whole log
+		}
+		events = append(events, e)
+	}
+	return events, sc.Err()
+}
+
+// ReadingMinutesByBook aggregates logged reading time per book title.
+func (s *Store) ReadingMinutesByBook() (map[string]float64, error) {
+	events, err := s.All()
+	if err != nil {
+		return nil, err
+	}
+	totals := make(map[string]float64)
+	for _, e := range events {
+		if e.Type != EventReading {
+			continue
+		}
+		key := e.Title
+		if key == "" {
+			key = e.BookID
+		}
+		totals[key] += e.Minutes
+	}
+	return totals, nil
+}
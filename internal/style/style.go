@@ -0,0 +1,56 @@
+// Package style decides whether CLI and TUI output may use color and
+// emoji, honoring explicit flags and conventional environment signals.
+package style
+
+import (
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"golang.org/x/term"
+)
+
+// Policy is the effective output policy for this process.
+type Policy struct {
+	Color bool
+	Emoji bool
+}
+
+// current is the applied policy; output defaults to plain until Apply
+// runs, which keeps piped output clean even if setup is skipped.
+var current = Policy{}
+
+// Detect derives the output policy from flags and the environment:
+// NO_COLOR, TERM=dumb, and writing to a non-TTY all disable decoration.
+func Detect(noColor, noEmoji bool) Policy {
+	plain := noColor ||
+		os.Getenv("NO_COLOR") != "" ||
+		os.Getenv("TERM") == "dumb" ||
+		!term.IsTerminal(int(os.Stdout.Fd()))
+	return Policy{
+		Color: !plain,
+		Emoji: !plain && !noEmoji,
+	}
+}
+
+// Apply installs the policy process-wide: lipgloss (used by both the
+// TUI and styled CLI output) drops to plain ASCII when color is off.
+func Apply(p Policy) {
+	current = p
+	if !p.Color {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+}
+
+// Current returns the applied policy.
+func Current() Policy {
+	return current
+}
+
+// Emoji returns the given emoji prefix when allowed, empty otherwise.
+func Emoji(s string) string {
+	if current.Emoji {
+		return s
+	}
+	return ""
+}
@@ -0,0 +1,113 @@
+// Package transcript parses WebVTT captions and renders them as SRT,
+// VTT, or plain text, so video course transcripts can be searched and
+// read offline.
+package transcript
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Cue is one caption: a time range and its text.
+type Cue struct {
+	Start time.Duration
+	End   time.Duration
+	Text  string
+}
+
+// cueTags matches WebVTT voice and formatting tags, which plain text
+// and SRT output drop.
+var cueTags = regexp.MustCompile(`<[^>]*>`)
+
+// timing matches a WebVTT cue timing line.
+var timing = regexp.MustCompile(`(\d+:)?(\d{1,2}):(\d{2})[.,](\d{3})\s+-->\s+(\d+:)?(\d{1,2}):(\d{2})[.,](\d{3})`)
+
+// ParseVTT extracts the cues from a WebVTT document, tolerating the
+// header, NOTE and STYLE blocks, and cue identifiers.
+func ParseVTT(data []byte) []Cue {
+	var cues []Cue
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		m := timing.FindStringSubmatch(lines[i])
+		if m == nil {
+			continue
+		}
+		cue := Cue{
+			Start: parseStamp(m[1], m[2], m[3], m[4]),
+			End:   parseStamp(m[5], m[6], m[7], m[8]),
+		}
+		var text []string
+		for i++; i < len(lines) && strings.TrimSpace(lines[i]) != ""; i++ {
+			text = append(text, strings.TrimSpace(cueTags.ReplaceAllString(lines[i], "")))
+		}
+		cue.Text = strings.Join(text, "\n")
+		if cue.Text != "" {
+			cues = append(cues, cue)
+		}
+	}
+	return cues
+}
+
+func parseStamp(hours, minutes, seconds, millis string) time.Duration {
+	var h, m, s, ms int
+	fmt.Sscanf(strings.TrimSuffix(hours, ":"), "%d", &h)
+	fmt.Sscanf(minutes, "%d", &m)
+	fmt.Sscanf(seconds, "%d", &s)
+	fmt.Sscanf(millis, "%d", &ms)
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute +
+		time.Duration(s)*time.Second + time.Duration(ms)*time.Millisecond
+}
+
+// SRT renders the cues as SubRip, the format most players accept.
+func SRT(cues []Cue) []byte {
+	var b strings.Builder
+	for i, cue := range cues {
+		fmt.Fprintf(&b, "%d\n%s --> %s\n%s\n\n", i+1, srtStamp(cue.Start), srtStamp(cue.End), cue.Text)
+	}
+	return []byte(b.String())
+}
+
+// VTT renders the cues back out as a minimal WebVTT document.
+func VTT(cues []Cue) []byte {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n\n")
+	for _, cue := range cues {
+		fmt.Fprintf(&b, "%s --> %s\n%s\n\n", vttStamp(cue.Start), vttStamp(cue.End), cue.Text)
+	}
+	return []byte(b.String())
+}
+
+// Text renders the cues as plain prose, collapsing the repeated lines
+// roll-up captions produce.
+func Text(cues []Cue) []byte {
+	var b strings.Builder
+	last := ""
+	for _, cue := range cues {
+		for _, line := range strings.Split(cue.Text, "\n") {
+			if line == "" || line == last {
+				continue
+			}
+			last = line
+			b.WriteString(line + "\n")
+		}
+	}
+	return []byte(b.String())
+}
+
+func srtStamp(d time.Duration) string {
+	return stamp(d, ",")
+}
+
+func vttStamp(d time.Duration) string {
+	return stamp(d, ".")
+}
+
+func stamp(d time.Duration, sep string) string {
+	h := d / time.Hour
+	m := (d % time.Hour) / time.Minute
+	s := (d % time.Minute) / time.Second
+	ms := (d % time.Second) / time.Millisecond
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", h, m, s, sep, ms)
+}
@@ -0,0 +1,45 @@
+package transcript
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleVTT = `WEBVTT
+
+NOTE a comment
+
+1
+00:00:01.000 --> 00:00:03.500
+<v Speaker>Welcome to the course.</v>
+
+00:01:00.000 --> 00:01:02.000
+Let's get started.
+Let's get started.
+`
+
+func TestParseVTT(t *testing.T) {
+	cues := ParseVTT([]byte(sampleVTT))
+	if len(cues) != 2 {
+		t.Fatalf("got %d cues, want 2", len(cues))
+	}
+	if cues[0].Start != time.Second || cues[0].End != 3500*time.Millisecond {
+		t.Errorf("cue 0 timing = %v --> %v", cues[0].Start, cues[0].End)
+	}
+	if cues[0].Text != "Welcome to the course." {
+		t.Errorf("cue 0 text = %q (tags should be stripped)", cues[0].Text)
+	}
+}
+
+func TestSRTAndTextRendering(t *testing.T) {
+	cues := ParseVTT([]byte(sampleVTT))
+	srt := string(SRT(cues))
+	if !strings.Contains(srt, "1\n00:00:01,000 --> 00:00:03,500") {
+		t.Errorf("bad SRT output:\n%s", srt)
+	}
+	text := string(Text(cues))
+	if strings.Count(text, "Let's get started.") != 1 {
+		t.Errorf("roll-up repeat not collapsed:\n%s", text)
+	}
+}
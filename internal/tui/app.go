@@ -0,0 +1,687 @@
+// Package tui implements the interactive terminal interface built on
+// Bubble Tea.
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/tuannvm/koreilly/internal/auth"
+	"github.com/tuannvm/koreilly/internal/catalog"
+	"github.com/tuannvm/koreilly/internal/config"
+	"github.com/tuannvm/koreilly/internal/downloader"
+	"github.com/tuannvm/koreilly/internal/progress"
+	"github.com/tuannvm/koreilly/internal/services/oreilly"
+	"github.com/tuannvm/koreilly/internal/stats"
+)
+
+var (
+	titleStyle    = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	selectedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("170")).Bold(true)
+	dimStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	errStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+)
+
+// formatFilters are the values the format toggle cycles through; empty
+// means no filter.
+var formatFilters = []string{"", "book", "video", "audiobook"}
+
+// languageFilters are the values the language toggle cycles through;
+// empty means no filter.
+var languageFilters = []string{"", "en", "es", "de", "fr", "pt", "ja", "zh"}
+
+type searchResultMsg struct {
+	result *oreilly.SearchResult
+	more   bool // appending a follow-up page rather than a fresh search
+	err    error
+}
+
+type detailMsg struct {
+	id   string
+	book *oreilly.Book
+	err  error
+}
+
+type bulkDoneMsg struct {
+	downloaded int
+	failed     int
+	lastErr    error
+}
+
+// App is the root Bubble Tea model.
+type App struct {
+	svc     *oreilly.Service
+	authSvc *auth.Service
+
+	query     textinput.Model
+	results   []oreilly.Book
+	total     int
+	next      string
+	cursor    int
+	searching bool
+	loading   bool // fetching the next page for infinite scroll
+	err       error
+
+	// active filters, cycled with keybindings
+	formatIdx   int
+	languageIdx int
+
+	// first-run sign-in form; non-nil until a token is stored
+	login *loginForm
+
+	// reading mode; non-nil while a book is open in the reader
+	reader *reader
+
+	// download queue view; non-nil while open
+	queue  *queueView
+	width  int
+	height int
+
+	// lastRead offers resuming the previous session's position
+	lastRead *progress.Position
+
+	// detail pane metadata, cached per product so moving the cursor
+	// back never refetches
+	details       map[string]*oreilly.Book
+	detailPending map[string]bool
+	detailErrs    map[string]string
+
+	// cover thumbnails for the detail pane, rendered once per product
+	coverProto   coverProtocol
+	covers       map[string]renderedCover
+	coverPending map[string]bool
+	coverFailed  map[string]bool
+
+	// multi-select: tab enters selection mode, space marks titles, and
+	// enter downloads everything marked in one batch
+	selecting   bool
+	marked      map[string]bool
+	bulkRunning bool
+	bulkMsg     string
+
+	// pomodoro-style reading session timer
+	stats      *stats.Store
+	timerStart time.Time
+	timerBook  oreilly.Book
+	timerMsg   string
+}
+
+type timerTickMsg time.Time
+
+func timerTick() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg { return timerTickMsg(t) })
+}
+
+// Run starts the TUI and blocks until it exits.
+func Run() error {
+	app := newApp()
+	_, err := tea.NewProgram(app, tea.WithAltScreen()).Run()
+	return err
+}
+
+func newApp() *App {
+	q := textinput.New()
+	q.Placeholder = "Search the O'Reilly library..."
+	q.Focus()
+	q.CharLimit = 128
+	app := &App{
+		svc:           oreilly.NewService(),
+		authSvc:       auth.NewService(),
+		stats:         stats.NewStore(),
+		query:         q,
+		details:       make(map[string]*oreilly.Book),
+		detailPending: make(map[string]bool),
+		detailErrs:    make(map[string]string),
+		coverProto:    detectCoverProtocol(),
+		covers:        make(map[string]renderedCover),
+		coverPending:  make(map[string]bool),
+		coverFailed:   make(map[string]bool),
+		marked:        make(map[string]bool),
+	}
+	// High contrast restyles the whole TUI, not just the reading pane,
+	// so the list, detail pane, and hints stay legible too.
+	if cfg, err := config.Load(); err == nil && cfg.Reader.Theme == "high-contrast" {
+		applyHighContrast()
+	}
+	// Best-effort: the resume offer just disappears when the progress
+	// store is unreadable.
+	app.lastRead, _ = progress.NewStore().Last()
+	// Without a stored token, start on the sign-in form instead of
+	// letting the first search fail with an auth error.
+	if !app.authSvc.IsAuthenticated() {
+		app.login = newLoginForm(app.authSvc)
+	}
+	return app
+}
+
+// Init implements tea.Model.
+func (a *App) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (a *App) searchOptions() oreilly.SearchOptions {
+	opts := oreilly.SearchOptions{}
+	if f := formatFilters[a.formatIdx]; f != "" {
+		opts.Formats = []string{f}
+	}
+	if l := languageFilters[a.languageIdx]; l != "" {
+		opts.Languages = []string{l}
+	}
+	return opts
+}
+
+func (a *App) search() tea.Cmd {
+	query := a.query.Value()
+	opts := a.searchOptions()
+	return func() tea.Msg {
+		jwt, err := a.authSvc.Token()
+		if err != nil {
+			return searchResultMsg{err: err}
+		}
+		result, err := a.svc.SearchBooks(context.Background(), jwt, query, opts)
+		return searchResultMsg{result: result, err: err}
+	}
+}
+
+// loadMore follows the next cursor so scrolling past the bottom keeps
+// filling the list.
+func (a *App) loadMore() tea.Cmd {
+	next := a.next
+	return func() tea.Msg {
+		jwt, err := a.authSvc.Token()
+		if err != nil {
+			return searchResultMsg{err: err, more: true}
+		}
+		result, err := a.svc.SearchBooksPage(context.Background(), jwt, next)
+		return searchResultMsg{result: result, err: err, more: true}
+	}
+}
+
+// fetchDetail loads full metadata for the highlighted result, unless it
+// is already cached or in flight.
+func (a *App) fetchDetail() tea.Cmd {
+	if a.cursor >= len(a.results) {
+		return nil
+	}
+	id := a.results[a.cursor].ProductID
+	if id == "" || a.details[id] != nil || a.detailPending[id] {
+		return nil
+	}
+	a.detailPending[id] = true
+	return func() tea.Msg {
+		jwt, err := a.authSvc.Token()
+		if err != nil {
+			return detailMsg{id: id, err: err}
+		}
+		book, err := a.svc.FetchBookDetail(context.Background(), jwt, id)
+		return detailMsg{id: id, book: book, err: err}
+	}
+}
+
+// Update implements tea.Model.
+func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		a.width, a.height = size.Width, size.Height
+		if a.reader != nil {
+			a.reader.setSize(size.Width, size.Height)
+		}
+		return a, nil
+	}
+	// Until sign-in succeeds, the login form owns the event loop.
+	if a.login != nil {
+		open, cmd := a.login.update(msg)
+		if !open {
+			a.login = nil
+		}
+		return a, cmd
+	}
+	// While a book is open, the reader owns the event loop.
+	if a.reader != nil {
+		open, cmd := a.reader.update(msg)
+		if !open {
+			a.reader = nil
+		}
+		return a, cmd
+	}
+	if a.queue != nil {
+		open, cmd := a.queue.update(msg)
+		if !open {
+			a.queue = nil
+		}
+		return a, cmd
+	}
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if a.selecting {
+			return a.updateSelecting(msg)
+		}
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			// Status messages stay on screen until dismissed rather
+			// than timing out; the first esc clears them.
+			if msg.String() == "esc" && (a.bulkMsg != "" || a.timerMsg != "") {
+				a.bulkMsg = ""
+				a.timerMsg = ""
+				return a, nil
+			}
+			return a, tea.Quit
+		case "tab":
+			if len(a.results) > 0 {
+				a.selecting = true
+			}
+			return a, nil
+		case "enter":
+			if a.query.Value() != "" {
+				a.searching = true
+				a.err = nil
+				return a, a.search()
+			}
+		case "ctrl+f":
+			a.formatIdx = (a.formatIdx + 1) % len(formatFilters)
+			return a, nil
+		case "ctrl+l":
+			a.languageIdx = (a.languageIdx + 1) % len(languageFilters)
+			return a, nil
+		case "ctrl+p":
+			return a, a.toggleTimer()
+		case "ctrl+q":
+			var cmd tea.Cmd
+			a.queue, cmd = openQueueView(a.svc, a.authSvc)
+			return a, cmd
+		case "ctrl+o":
+			if a.lastRead != nil {
+				var cmd tea.Cmd
+				a.reader, cmd = openReaderAt(a.svc, a.authSvc, *a.lastRead)
+				if a.width > 0 {
+					a.reader.setSize(a.width, a.height)
+				}
+				return a, cmd
+			}
+			return a, nil
+		case "ctrl+r":
+			if a.cursor < len(a.results) {
+				var cmd tea.Cmd
+				a.reader, cmd = openReader(a.svc, a.authSvc, a.results[a.cursor])
+				if a.width > 0 {
+					a.reader.setSize(a.width, a.height)
+				}
+				return a, cmd
+			}
+			return a, nil
+		case "up":
+			if a.cursor > 0 {
+				a.cursor--
+			}
+			return a, a.fetchDetail()
+		case "down":
+			if a.cursor < len(a.results)-1 {
+				a.cursor++
+			}
+			// Reaching the bottom of a page triggers an infinite-scroll
+			// fetch of the next cursor.
+			if a.cursor == len(a.results)-1 && a.next != "" && !a.loading {
+				a.loading = true
+				return a, tea.Batch(a.loadMore(), a.fetchDetail())
+			}
+			return a, a.fetchDetail()
+		}
+	case searchResultMsg:
+		a.searching = false
+		a.loading = false
+		if msg.err != nil {
+			a.err = msg.err
+			return a, nil
+		}
+		if msg.more {
+			a.results = append(a.results, msg.result.Results...)
+		} else {
+			a.results = msg.result.Results
+			a.cursor = 0
+		}
+		a.total = msg.result.Total
+		a.next = msg.result.Next
+		return a, a.fetchDetail()
+	case detailMsg:
+		delete(a.detailPending, msg.id)
+		if msg.err != nil {
+			a.detailErrs[msg.id] = msg.err.Error()
+			return a, nil
+		}
+		delete(a.detailErrs, msg.id)
+		a.details[msg.id] = msg.book
+		// The detail payload carries the cover URL; fetch and render it
+		// once in the background.
+		if url := msg.book.CoverURL; url != "" && !a.coverPending[msg.id] && !a.coverFailed[msg.id] {
+			if _, ok := a.covers[msg.id]; !ok {
+				a.coverPending[msg.id] = true
+				return a, fetchCover(msg.id, url, a.coverProto)
+			}
+		}
+		return a, nil
+	case coverMsg:
+		delete(a.coverPending, msg.id)
+		if msg.err != nil {
+			// A missing or undecodable cover just leaves the placeholder.
+			a.coverFailed[msg.id] = true
+			return a, nil
+		}
+		a.covers[msg.id] = msg.cover
+		return a, nil
+	case bulkDoneMsg:
+		a.bulkRunning = false
+		a.selecting = false
+		a.marked = make(map[string]bool)
+		if msg.lastErr != nil {
+			a.bulkMsg = fmt.Sprintf("downloaded %d, %d failed: %v", msg.downloaded, msg.failed, msg.lastErr)
+		} else {
+			a.bulkMsg = fmt.Sprintf("downloaded %d titles", msg.downloaded)
+		}
+		return a, nil
+	case timerTickMsg:
+		if !a.timerStart.IsZero() {
+			return a, timerTick()
+		}
+		return a, nil
+	}
+	var cmd tea.Cmd
+	a.query, cmd = a.query.Update(msg)
+	return a, cmd
+}
+
+// View implements tea.Model.
+func (a *App) View() string {
+	if a.login != nil {
+		return a.login.view()
+	}
+	if a.reader != nil {
+		return a.reader.view()
+	}
+	if a.queue != nil {
+		return a.queue.view()
+	}
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("goreilly") + "\n\n")
+	b.WriteString(a.query.View() + "\n")
+	b.WriteString(dimStyle.Render(a.filterLine()) + "\n")
+	if a.lastRead != nil {
+		b.WriteString(dimStyle.Render(fmt.Sprintf("ctrl+o: resume %s — %s", a.lastRead.BookTitle, a.lastRead.Chapter)) + "\n")
+	}
+	b.WriteString("\n")
+
+	switch {
+	case a.searching:
+		b.WriteString("Searching...\n")
+	case a.err != nil:
+		b.WriteString(errStyle.Render(a.err.Error()) + "\n")
+	default:
+		var list strings.Builder
+		for i, book := range a.results {
+			line := fmt.Sprintf("%-8s %s — %s", book.Format, book.Title, strings.Join(book.Authors, ", "))
+			if book.Level != "" {
+				line += "  [" + book.Level + "]"
+			}
+			if a.selecting || len(a.marked) > 0 {
+				mark := "[ ] "
+				if a.marked[book.ProductID] {
+					mark = "[x] "
+				}
+				line = mark + line
+			}
+			if i == a.cursor {
+				line = selectedStyle.Render("> " + line)
+			} else {
+				line = "  " + line
+			}
+			list.WriteString(line + "\n")
+		}
+		if a.total > 0 {
+			list.WriteString(dimStyle.Render(fmt.Sprintf("\n%d results", a.total)) + "\n")
+		}
+		body := list.String()
+		// On wide terminals the highlighted result gets a detail pane
+		// alongside the list.
+		if pane := a.detailPane(); pane != "" && a.width >= 2*detailPaneWidth {
+			body = lipgloss.JoinHorizontal(lipgloss.Top,
+				lipgloss.NewStyle().Width(a.width-detailPaneWidth-2).Render(body),
+				lipgloss.NewStyle().Width(detailPaneWidth).Render(pane)) + "\n"
+			// Layout helpers measure graphics escapes as printable text,
+			// so the native cover goes above the columns instead of
+			// inside the pane; half-block terminals get it in the pane.
+			if cover := a.nativeCover(); cover != "" {
+				body = cover + "\n" + body
+			}
+		}
+		b.WriteString(body)
+	}
+	if line := a.timerLine(); line != "" {
+		b.WriteString("\n" + dimStyle.Render(line) + "\n")
+	}
+	switch {
+	case a.bulkRunning:
+		b.WriteString("\n" + dimStyle.Render(fmt.Sprintf("downloading %d marked titles...", len(a.marked))) + "\n")
+	case a.bulkMsg != "":
+		b.WriteString("\n" + dimStyle.Render(a.bulkMsg) + "\n")
+	}
+	if a.selecting {
+		b.WriteString(dimStyle.Render(fmt.Sprintf("\n%d marked — space: mark  enter/d: download marked  tab: back\n", len(a.marked))))
+	} else {
+		b.WriteString(dimStyle.Render("\nenter: search  ctrl+r: read  tab: multi-select  ctrl+q: queue  ctrl+f/ctrl+l: cycle format/language filter  ctrl+p: reading timer  esc: dismiss/quit\n"))
+	}
+	return b.String()
+}
+
+// updateSelecting handles keys while multi-select mode owns the list:
+// space marks the highlighted title, enter downloads everything marked.
+func (a *App) updateSelecting(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return a, tea.Quit
+	case "tab", "esc":
+		a.selecting = false
+		return a, nil
+	case " ":
+		if a.cursor < len(a.results) {
+			id := a.results[a.cursor].ProductID
+			if a.marked[id] {
+				delete(a.marked, id)
+			} else {
+				a.marked[id] = true
+			}
+		}
+		return a, nil
+	case "up":
+		if a.cursor > 0 {
+			a.cursor--
+		}
+		return a, a.fetchDetail()
+	case "down":
+		if a.cursor < len(a.results)-1 {
+			a.cursor++
+		}
+		if a.cursor == len(a.results)-1 && a.next != "" && !a.loading {
+			a.loading = true
+			return a, tea.Batch(a.loadMore(), a.fetchDetail())
+		}
+		return a, a.fetchDetail()
+	case "enter", "d":
+		return a, a.bulkDownload()
+	}
+	return a, nil
+}
+
+// bulkDownload enqueues every marked title into the persistent queue
+// and drains it in the background.
+func (a *App) bulkDownload() tea.Cmd {
+	if a.bulkRunning || len(a.marked) == 0 {
+		return nil
+	}
+	var books []oreilly.Book
+	for _, book := range a.results {
+		if a.marked[book.ProductID] {
+			books = append(books, book)
+		}
+	}
+	a.bulkRunning = true
+	a.bulkMsg = ""
+	return func() tea.Msg {
+		cfg, err := config.Load()
+		if err != nil {
+			return bulkDoneMsg{failed: len(books), lastErr: err}
+		}
+		jwt, err := a.authSvc.Token()
+		if err != nil {
+			return bulkDoneMsg{failed: len(books), lastErr: err}
+		}
+		q := downloader.NewQueue()
+		for _, book := range books {
+			if err := q.Add(book.ProductID, book.Title); err != nil {
+				return bulkDoneMsg{failed: len(books), lastErr: err}
+			}
+		}
+		if err := q.Process(context.Background(), cfg, a.svc, jwt, cfg.OutputDir, 2, nil); err != nil {
+			return bulkDoneMsg{failed: len(books), lastErr: err}
+		}
+		var done, failed int
+		var lastErr error
+		items, _ := q.List()
+		for _, item := range items {
+			switch item.Status {
+			case catalog.QueueDone:
+				done++
+			case catalog.QueueFailed:
+				failed++
+				lastErr = fmt.Errorf("%s: %s", item.ID, item.Error)
+			}
+		}
+		return bulkDoneMsg{downloaded: done, failed: failed, lastErr: lastErr}
+	}
+}
+
+// detailPaneWidth is how many columns the metadata panel takes on wide
+// terminals.
+const detailPaneWidth = 42
+
+// detailPane renders metadata for the highlighted result, or a loading
+// line while the fetch is in flight.
+func (a *App) detailPane() string {
+	if a.cursor >= len(a.results) {
+		return ""
+	}
+	sel := a.results[a.cursor]
+	if sel.ProductID == "" {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(selectedStyle.Render(sel.Title) + "\n")
+	// The half-block cover is SGR-only so it lays out like text; native
+	// graphics escapes would be mangled by the width wrapping here and
+	// are emitted by View instead.
+	if a.coverProto == coverNone {
+		if cv, ok := a.covers[sel.ProductID]; ok && cv.block != "" {
+			b.WriteString(cv.block + "\n")
+		} else {
+			b.WriteString(placeholderCover() + "\n")
+		}
+	}
+	if errText := a.detailErrs[sel.ProductID]; errText != "" {
+		b.WriteString(errStyle.Render(errText) + "\n")
+		return b.String()
+	}
+	d := a.details[sel.ProductID]
+	if d == nil {
+		b.WriteString(dimStyle.Render("Loading details...") + "\n")
+		return b.String()
+	}
+	if len(d.Authors) > 0 {
+		b.WriteString("by " + strings.Join(d.Authors, ", ") + "\n")
+	}
+	if d.Issued != "" {
+		b.WriteString("Published: " + d.Issued + "\n")
+	}
+	if d.Level != "" {
+		b.WriteString("Level: " + d.Level + "\n")
+	}
+	switch {
+	case d.Duration > 0:
+		b.WriteString("Duration: " + (time.Duration(d.Duration) * time.Second).Round(time.Minute).String() + "\n")
+	case d.VirtualPages > 0:
+		b.WriteString(fmt.Sprintf("Pages: %d\n", d.VirtualPages))
+	}
+	if len(d.Topics) > 0 {
+		b.WriteString("Topics: " + strings.Join(d.Topics, ", ") + "\n")
+	}
+	if d.Description != "" {
+		// Descriptions come back as HTML; reuse the reader's converter
+		// and keep the pane short.
+		text, _ := htmlToText(d.Description)
+		if r := []rune(text); len(r) > 600 {
+			text = string(r[:600]) + "…"
+		}
+		b.WriteString("\n" + dimStyle.Render(text) + "\n")
+	}
+	return b.String()
+}
+
+// nativeCover returns the graphics-protocol escape for the highlighted
+// result's cover, or empty when the terminal has none or it isn't
+// rendered yet.
+func (a *App) nativeCover() string {
+	if a.coverProto == coverNone || a.cursor >= len(a.results) {
+		return ""
+	}
+	return a.covers[a.results[a.cursor].ProductID].native
+}
+
+// toggleTimer starts a reading session for the selected book, or stops
+// the running one and logs it to the stats store.
+func (a *App) toggleTimer() tea.Cmd {
+	if a.timerStart.IsZero() {
+		if a.cursor >= len(a.results) {
+			return nil
+		}
+		a.timerBook = a.results[a.cursor]
+		a.timerStart = time.Now()
+		a.timerMsg = ""
+		return timerTick()
+	}
+	minutes := time.Since(a.timerStart).Minutes()
+	err := a.stats.Append(stats.Event{
+		Type:    stats.EventReading,
+		BookID:  a.timerBook.ProductID,
+		Title:   a.timerBook.Title,
+		Minutes: minutes,
+	})
+	if err != nil {
+		a.timerMsg = "failed to log session: " + err.Error()
+	} else {
+		a.timerMsg = fmt.Sprintf("logged %.1f min on %s", minutes, a.timerBook.Title)
+	}
+	a.timerStart = time.Time{}
+	return nil
+}
+
+func (a *App) timerLine() string {
+	if !a.timerStart.IsZero() {
+		elapsed := time.Since(a.timerStart).Round(time.Second)
+		return fmt.Sprintf("reading %s — %s (ctrl+p to stop)", a.timerBook.Title, elapsed)
+	}
+	return a.timerMsg
+}
+
+func (a *App) filterLine() string {
+	format := formatFilters[a.formatIdx]
+	if format == "" {
+		format = "any"
+	}
+	language := languageFilters[a.languageIdx]
+	if language == "" {
+		language = "any"
+	}
+	return fmt.Sprintf("filters: format=%s language=%s", format, language)
+}
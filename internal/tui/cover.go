@@ -0,0 +1,246 @@
+package tui
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"strings"
+
+	_ "image/gif"
+	_ "image/jpeg"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/tuannvm/koreilly/internal/client"
+)
+
+// coverProtocol identifies the terminal graphics protocol, if any, that
+// can show a real cover image.
+type coverProtocol int
+
+const (
+	coverNone coverProtocol = iota
+	coverKitty
+	coverITerm
+	coverSixel
+)
+
+// Cover rendering dimensions: cells for the protocols that scale to the
+// cell grid, pixels for sixel and the half-block fallback.
+const (
+	coverCols   = 18
+	coverRows   = 12
+	coverPixelW = 96
+	coverPixelH = 128
+	blockWidth  = 20
+)
+
+// detectCoverProtocol sniffs the terminal's graphics support from the
+// environment. Proper detection would query the terminal with DA1, but
+// that fights Bubble Tea for the tty; the environment heuristics cover
+// the terminals people actually use, and everything else gets the
+// unicode fallback.
+func detectCoverProtocol() coverProtocol {
+	term := os.Getenv("TERM")
+	switch {
+	case os.Getenv("KITTY_WINDOW_ID") != "" || strings.Contains(term, "kitty"):
+		return coverKitty
+	case os.Getenv("TERM_PROGRAM") == "iTerm.app" || os.Getenv("TERM_PROGRAM") == "WezTerm":
+		return coverITerm
+	case strings.Contains(term, "sixel") || strings.Contains(term, "mlterm") || strings.Contains(term, "foot"):
+		return coverSixel
+	}
+	return coverNone
+}
+
+// renderedCover holds both renderings of a fetched cover: the native
+// escape sequence (empty when the terminal has no graphics protocol)
+// and the SGR-only half-block version that survives lipgloss layout.
+type renderedCover struct {
+	native string
+	block  string
+}
+
+type coverMsg struct {
+	id    string
+	cover renderedCover
+	err   error
+}
+
+// fetchCover downloads and renders the cover for one book.
+func fetchCover(id, url string, proto coverProtocol) tea.Cmd {
+	return func() tea.Msg {
+		resp, err := client.New().Get(context.Background(), url, nil)
+		if err != nil {
+			return coverMsg{id: id, err: err}
+		}
+		defer resp.Body.Close()
+		raw, err := io.ReadAll(io.LimitReader(resp.Body, 2<<20))
+		if err != nil {
+			return coverMsg{id: id, err: err}
+		}
+		img, _, err := image.Decode(bytes.NewReader(raw))
+		if err != nil {
+			return coverMsg{id: id, err: fmt.Errorf("decoding cover: %w", err)}
+		}
+		cover := renderedCover{block: blockCover(img)}
+		switch proto {
+		case coverKitty:
+			cover.native = kittyCover(img)
+		case coverITerm:
+			cover.native = itermCover(raw)
+		case coverSixel:
+			cover.native = sixelCover(scaleCover(img, coverPixelW, coverPixelH))
+		}
+		return coverMsg{id: id, cover: cover}
+	}
+}
+
+// placeholderCover is shown while the cover loads or when the fetch
+// fails — a plain box so the pane doesn't jump around.
+func placeholderCover() string {
+	inner := strings.Repeat(" ", blockWidth-2)
+	mid := "📚"
+	pad := (blockWidth - 4) / 2
+	lines := []string{
+		"╭" + strings.Repeat("─", blockWidth-2) + "╮",
+		"│" + inner + "│",
+		"│" + strings.Repeat(" ", pad) + mid + strings.Repeat(" ", blockWidth-4-pad) + "│",
+		"│" + inner + "│",
+		"╰" + strings.Repeat("─", blockWidth-2) + "╯",
+	}
+	return dimStyle.Render(strings.Join(lines, "\n"))
+}
+
+// scaleCover resizes img to w x h with nearest-neighbour sampling —
+// crude, but covers are small enough that quality doesn't matter.
+func scaleCover(img image.Image, w, h int) image.Image {
+	b := img.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		sy := b.Min.Y + y*b.Dy()/h
+		for x := 0; x < w; x++ {
+			sx := b.Min.X + x*b.Dx()/w
+			out.Set(x, y, img.At(sx, sy))
+		}
+	}
+	return out
+}
+
+// blockCover renders the image as truecolor half-blocks, two pixel rows
+// per text row. It uses raw SGR sequences, which every layout helper
+// treats as zero-width, so it is safe inside the detail pane.
+func blockCover(img image.Image) string {
+	h := blockWidth * 4 / 3
+	if h%2 == 1 {
+		h++
+	}
+	scaled := scaleCover(img, blockWidth, h)
+	var b strings.Builder
+	for y := 0; y < h; y += 2 {
+		for x := 0; x < blockWidth; x++ {
+			tr, tg, tb, _ := scaled.At(x, y).RGBA()
+			br, bg, bb, _ := scaled.At(x, y+1).RGBA()
+			fmt.Fprintf(&b, "\x1b[38;2;%d;%d;%dm\x1b[48;2;%d;%d;%dm▀",
+				tr>>8, tg>>8, tb>>8, br>>8, bg>>8, bb>>8)
+		}
+		b.WriteString("\x1b[0m\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// kittyCover encodes the image for the kitty graphics protocol: PNG
+// payload, base64, in 4096-byte chunks.
+func kittyCover(img image.Image) string {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return ""
+	}
+	data := base64.StdEncoding.EncodeToString(buf.Bytes())
+	var b strings.Builder
+	first := true
+	for len(data) > 0 {
+		n := len(data)
+		if n > 4096 {
+			n = 4096
+		}
+		chunk, more := data[:n], len(data) > n
+		switch {
+		case first:
+			fmt.Fprintf(&b, "\x1b_Ga=T,f=100,c=%d,r=%d,m=%d;%s\x1b\\", coverCols, coverRows, boolInt(more), chunk)
+		default:
+			fmt.Fprintf(&b, "\x1b_Gm=%d;%s\x1b\\", boolInt(more), chunk)
+		}
+		first = false
+		data = data[n:]
+	}
+	return b.String()
+}
+
+// itermCover wraps the raw image bytes in iTerm2's inline-image OSC,
+// which accepts any format the terminal can decode.
+func itermCover(raw []byte) string {
+	return fmt.Sprintf("\x1b]1337;File=inline=1;size=%d;width=%d;preserveAspectRatio=1:%s\x07",
+		len(raw), coverCols, base64.StdEncoding.EncodeToString(raw))
+}
+
+// sixelCover encodes the image as sixel data using a fixed 6x6x6 color
+// cube, the same palette approach img2sixel defaults to.
+func sixelCover(img image.Image) string {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	var sb strings.Builder
+	sb.WriteString("\x1bPq")
+	fmt.Fprintf(&sb, "\"1;1;%d;%d", w, h)
+	for i := 0; i < 216; i++ {
+		fmt.Fprintf(&sb, "#%d;2;%d;%d;%d", i, (i/36)*100/5, (i/6%6)*100/5, (i%6)*100/5)
+	}
+	// quantize once up front
+	pix := make([]int, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			r, g, bl, _ := img.At(b.Min.X+x, b.Min.Y+y).RGBA()
+			pix[y*w+x] = 36*int(r>>8)*6/256 + 6*int(g>>8)*6/256 + int(bl>>8)*6/256
+		}
+	}
+	for y := 0; y < h; y += 6 {
+		used := map[int]bool{}
+		for dy := 0; dy < 6 && y+dy < h; dy++ {
+			for x := 0; x < w; x++ {
+				used[pix[(y+dy)*w+x]] = true
+			}
+		}
+		first := true
+		for color := range used {
+			if !first {
+				sb.WriteString("$")
+			}
+			first = false
+			fmt.Fprintf(&sb, "#%d", color)
+			for x := 0; x < w; x++ {
+				bits := 0
+				for dy := 0; dy < 6 && y+dy < h; dy++ {
+					if pix[(y+dy)*w+x] == color {
+						bits |= 1 << dy
+					}
+				}
+				sb.WriteByte(byte(63 + bits))
+			}
+		}
+		sb.WriteString("-")
+	}
+	sb.WriteString("\x1b\\")
+	return sb.String()
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
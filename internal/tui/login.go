@@ -0,0 +1,203 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/tuannvm/koreilly/internal/auth"
+)
+
+// loginForm is shown before the search screen when no session token is
+// stored, so first-run users never hit a bare auth error. It offers the
+// email/password flow and a paste-a-token fallback.
+type loginForm struct {
+	authSvc *auth.Service
+
+	email    textinput.Model
+	password textinput.Model
+	jwt      textinput.Model
+	otp      textinput.Model
+
+	jwtMode    bool   // pasting an orm-jwt instead of logging in
+	mfaToken   string // non-empty while waiting for a one-time code
+	focus      int    // 0 = email, 1 = password
+	submitting bool
+	err        error
+}
+
+type loginDoneMsg struct {
+	err error
+}
+
+func newLoginForm(authSvc *auth.Service) *loginForm {
+	email := textinput.New()
+	email.Placeholder = "you@example.com"
+	email.CharLimit = 128
+	email.Focus()
+
+	password := textinput.New()
+	password.Placeholder = "password"
+	password.CharLimit = 128
+	password.EchoMode = textinput.EchoPassword
+
+	jwt := textinput.New()
+	jwt.Placeholder = "paste orm-jwt"
+	jwt.CharLimit = 4096
+	jwt.EchoMode = textinput.EchoPassword
+
+	otp := textinput.New()
+	otp.Placeholder = "123456"
+	otp.CharLimit = 16
+
+	return &loginForm{authSvc: authSvc, email: email, password: password, jwt: jwt, otp: otp}
+}
+
+// update handles one message; it returns false once login succeeded and
+// the form should be dismissed.
+func (f *loginForm) update(msg tea.Msg) (bool, tea.Cmd) {
+	switch msg := msg.(type) {
+	case loginDoneMsg:
+		f.submitting = false
+		if msg.err != nil {
+			var mfa *auth.MFAChallenge
+			if errors.As(msg.err, &mfa) {
+				f.mfaToken = mfa.Token
+				f.err = nil
+				f.syncFocus()
+				return true, nil
+			}
+			f.err = msg.err
+			return true, nil
+		}
+		return false, nil
+	case tea.KeyMsg:
+		if f.submitting {
+			return true, nil
+		}
+		switch msg.String() {
+		case "ctrl+c":
+			return true, tea.Quit
+		case "esc":
+			// esc backs out of the one-time-code step; elsewhere it quits.
+			if f.mfaToken != "" {
+				f.mfaToken = ""
+				f.otp.SetValue("")
+				f.syncFocus()
+				return true, nil
+			}
+			return true, tea.Quit
+		case "ctrl+t":
+			if f.mfaToken != "" {
+				return true, nil
+			}
+			f.jwtMode = !f.jwtMode
+			f.err = nil
+			f.syncFocus()
+			return true, nil
+		case "tab", "shift+tab":
+			if !f.jwtMode && f.mfaToken == "" {
+				f.focus = 1 - f.focus
+				f.syncFocus()
+			}
+			return true, nil
+		case "enter":
+			if f.mfaToken != "" {
+				return true, f.submit(&auth.OTPLogin{
+					Service:  f.authSvc,
+					MFAToken: f.mfaToken,
+					Code:     f.otp.Value(),
+				})
+			}
+			if f.jwtMode {
+				return true, f.submit(&auth.StaticJWT{JWT: strings.TrimSpace(f.jwt.Value())})
+			}
+			// Enter moves from email to password, then submits.
+			if f.focus == 0 {
+				f.focus = 1
+				f.syncFocus()
+				return true, nil
+			}
+			return true, f.submit(&auth.PasswordLogin{
+				Service:  f.authSvc,
+				Email:    strings.TrimSpace(f.email.Value()),
+				Password: f.password.Value(),
+			})
+		}
+	}
+	var cmd tea.Cmd
+	switch {
+	case f.mfaToken != "":
+		f.otp, cmd = f.otp.Update(msg)
+	case f.jwtMode:
+		f.jwt, cmd = f.jwt.Update(msg)
+	case f.focus == 0:
+		f.email, cmd = f.email.Update(msg)
+	default:
+		f.password, cmd = f.password.Update(msg)
+	}
+	return true, cmd
+}
+
+func (f *loginForm) syncFocus() {
+	f.email.Blur()
+	f.password.Blur()
+	f.jwt.Blur()
+	f.otp.Blur()
+	switch {
+	case f.mfaToken != "":
+		f.otp.Focus()
+	case f.jwtMode:
+		f.jwt.Focus()
+	case f.focus == 0:
+		f.email.Focus()
+	default:
+		f.password.Focus()
+	}
+}
+
+func (f *loginForm) submit(strategy auth.Strategy) tea.Cmd {
+	f.submitting = true
+	f.err = nil
+	return func() tea.Msg {
+		_, err := f.authSvc.Login(context.Background(), strategy)
+		return loginDoneMsg{err: err}
+	}
+}
+
+func (f *loginForm) view() string {
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("goreilly — sign in") + "\n\n")
+	switch {
+	case f.mfaToken != "":
+		b.WriteString("One-time code\n")
+		b.WriteString(f.otp.View() + "\n")
+	case f.jwtMode:
+		b.WriteString("Session token\n")
+		b.WriteString(f.jwt.View() + "\n")
+	default:
+		b.WriteString("Email\n")
+		b.WriteString(f.email.View() + "\n")
+		b.WriteString("Password\n")
+		b.WriteString(f.password.View() + "\n")
+	}
+	b.WriteString("\n")
+	switch {
+	case f.submitting:
+		b.WriteString("Signing in...\n")
+	case f.err != nil:
+		b.WriteString(errStyle.Render(f.err.Error()) + "\n")
+	}
+	switch {
+	case f.mfaToken != "":
+		b.WriteString(dimStyle.Render("\nenter: verify code  esc: back\n"))
+	case f.jwtMode:
+		b.WriteString(dimStyle.Render("\nenter: sign in  ctrl+t: use email/password  esc: quit\n"))
+	default:
+		b.WriteString(dimStyle.Render("\ntab: switch field  enter: sign in  ctrl+t: paste a token instead  esc: quit\n"))
+	}
+	return b.String()
+}
@@ -0,0 +1,166 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/tuannvm/koreilly/internal/auth"
+	"github.com/tuannvm/koreilly/internal/catalog"
+	"github.com/tuannvm/koreilly/internal/config"
+	"github.com/tuannvm/koreilly/internal/downloader"
+	"github.com/tuannvm/koreilly/internal/services/oreilly"
+)
+
+// queueView shows the persistent download queue and lets items be
+// paused, resumed, removed, or drained without leaving the TUI.
+type queueView struct {
+	svc     *oreilly.Service
+	authSvc *auth.Service
+	q       *downloader.Queue
+
+	items   []catalog.QueueItem
+	cursor  int
+	running bool
+	status  string
+	err     error
+}
+
+type queueItemsMsg struct {
+	items []catalog.QueueItem
+	err   error
+}
+
+type queueRunDoneMsg struct {
+	err error
+}
+
+func openQueueView(svc *oreilly.Service, authSvc *auth.Service) (*queueView, tea.Cmd) {
+	v := &queueView{svc: svc, authSvc: authSvc, q: downloader.NewQueue()}
+	return v, v.refresh()
+}
+
+func (v *queueView) refresh() tea.Cmd {
+	return func() tea.Msg {
+		items, err := v.q.List()
+		return queueItemsMsg{items: items, err: err}
+	}
+}
+
+// update handles one message; it returns false once the view should
+// close.
+func (v *queueView) update(msg tea.Msg) (bool, tea.Cmd) {
+	switch msg := msg.(type) {
+	case queueItemsMsg:
+		v.err = msg.err
+		v.items = msg.items
+		if v.cursor >= len(v.items) && v.cursor > 0 {
+			v.cursor = len(v.items) - 1
+		}
+		return true, nil
+	case queueRunDoneMsg:
+		v.running = false
+		if msg.err != nil {
+			v.err = msg.err
+		} else {
+			v.status = "queue run finished"
+		}
+		return true, v.refresh()
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return true, tea.Quit
+		case "q", "esc", "ctrl+q":
+			return false, nil
+		case "up", "k":
+			if v.cursor > 0 {
+				v.cursor--
+			}
+			return true, nil
+		case "down", "j":
+			if v.cursor < len(v.items)-1 {
+				v.cursor++
+			}
+			return true, nil
+		case "p":
+			return true, v.act("paused", v.q.Pause)
+		case "r":
+			return true, v.act("requeued", v.q.Resume)
+		case "d":
+			return true, v.act("removed", v.q.Remove)
+		case "s":
+			return true, v.run()
+		}
+	}
+	return true, nil
+}
+
+// act applies an operation to the highlighted item and reloads.
+func (v *queueView) act(verb string, op func(string) error) tea.Cmd {
+	if v.cursor >= len(v.items) {
+		return nil
+	}
+	id := v.items[v.cursor].ID
+	if err := op(id); err != nil {
+		v.err = err
+		return nil
+	}
+	v.status = fmt.Sprintf("%s %s", verb, id)
+	return v.refresh()
+}
+
+// run drains the queue in the background with modest concurrency.
+func (v *queueView) run() tea.Cmd {
+	if v.running {
+		return nil
+	}
+	v.running = true
+	v.status = ""
+	return func() tea.Msg {
+		cfg, err := config.Load()
+		if err != nil {
+			return queueRunDoneMsg{err: err}
+		}
+		jwt, err := v.authSvc.Token()
+		if err != nil {
+			return queueRunDoneMsg{err: err}
+		}
+		err = v.q.Process(context.Background(), cfg, v.svc, jwt, cfg.OutputDir, 2, nil)
+		return queueRunDoneMsg{err: err}
+	}
+}
+
+func (v *queueView) view() string {
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("download queue") + "\n\n")
+	if len(v.items) == 0 {
+		b.WriteString(dimStyle.Render("Queue is empty — mark results with tab+space and enqueue them.") + "\n")
+	}
+	for i, item := range v.items {
+		line := fmt.Sprintf("%-8s %s", item.Status, item.ID)
+		if item.Title != "" {
+			line += " — " + item.Title
+		}
+		if item.Error != "" {
+			line += "  (" + item.Error + ")"
+		}
+		if i == v.cursor {
+			line = selectedStyle.Render("> " + line)
+		} else {
+			line = "  " + line
+		}
+		b.WriteString(line + "\n")
+	}
+	switch {
+	case v.running:
+		b.WriteString("\n" + dimStyle.Render("running queue...") + "\n")
+	case v.err != nil:
+		b.WriteString("\n" + errStyle.Render(v.err.Error()) + "\n")
+	case v.status != "":
+		b.WriteString("\n" + dimStyle.Render(v.status) + "\n")
+	}
+	b.WriteString(dimStyle.Render("\ns: start  p: pause  r: resume  d: remove  esc: back\n"))
+	return b.String()
+}
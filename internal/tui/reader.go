@@ -0,0 +1,731 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/tuannvm/koreilly/internal/auth"
+	"github.com/tuannvm/koreilly/internal/bookmarks"
+	"github.com/tuannvm/koreilly/internal/config"
+	"github.com/tuannvm/koreilly/internal/dict"
+	"github.com/tuannvm/koreilly/internal/downloader"
+	"github.com/tuannvm/koreilly/internal/progress"
+	"github.com/tuannvm/koreilly/internal/sanitize"
+	"github.com/tuannvm/koreilly/internal/services/oreilly"
+	"github.com/tuannvm/koreilly/internal/util"
+)
+
+// prefetchDepth is how many chapters ahead of the one being read are
+// fetched in the background, so page-forward is instant on slow links.
+const prefetchDepth = 2
+
+type readerOpenedMsg struct {
+	chapters []oreilly.Chapter
+	err      error
+}
+
+type chapterMsg struct {
+	idx  int
+	text string
+	code []string
+	err  error
+}
+
+// reader is the in-terminal reading mode: one chapter at a time in a
+// scrollable viewport, with upcoming chapters prefetched into a cache.
+type reader struct {
+	svc     *oreilly.Service
+	authSvc *auth.Service
+
+	bookID   string
+	title    string
+	chapters []oreilly.Chapter
+	idx      int
+
+	viewport viewport.Model
+	ready    bool
+	loading  bool
+	err      error
+
+	// cache holds rendered chapter text by index; pending marks
+	// in-flight fetches so a chapter is never requested twice.
+	cache   map[int]string
+	pending map[int]bool
+
+	// code blocks of each cached chapter, with a selection index for
+	// copy/export within the current chapter
+	codeBlocks map[int][]string
+	codeIdx    int
+
+	// bookmark state: naming a new bookmark or picking from the list
+	marks      *bookmarks.Store
+	markInput  textinput.Model
+	naming     bool
+	markList   []bookmarks.Bookmark
+	markCursor int
+	listing    bool
+	statusMsg  string
+
+	// TOC browser: pick any chapter from the table of contents
+	tocListing bool
+	tocCursor  int
+
+	// dictionary lookup popup
+	defInput   textinput.Model
+	defPrompt  bool
+	definition string
+
+	// presentation settings from the reader.* config keys
+	themeIdx int
+	maxWidth int
+	margin   int
+	width    int
+
+	// local progress store, plus the position to restore after the TOC
+	// loads when resuming a session
+	progress     *progress.Store
+	resumeIdx    int
+	resumeOffset int
+}
+
+type definitionMsg struct {
+	word string
+	text string
+	err  error
+}
+
+type downloadDoneMsg struct {
+	path string
+	err  error
+}
+
+// openReader starts reading mode for a book; the TOC loads async.
+func openReader(svc *oreilly.Service, authSvc *auth.Service, book oreilly.Book) (*reader, tea.Cmd) {
+	r := &reader{
+		svc:     svc,
+		authSvc: authSvc,
+		bookID:  book.ProductID,
+		title:   book.Title,
+		loading:    true,
+		cache:      make(map[int]string),
+		pending:    make(map[int]bool),
+		codeBlocks: make(map[int][]string),
+		marks:      bookmarks.NewStore(),
+		progress:   progress.NewStore(),
+		maxWidth:   80,
+		margin:     2,
+	}
+	if cfg, err := config.Load(); err == nil {
+		r.themeIdx = themeIndex(cfg.Reader.Theme)
+		if cfg.Reader.MaxWidth > 0 {
+			r.maxWidth = cfg.Reader.MaxWidth
+		}
+		if cfg.Reader.Margin >= 0 {
+			r.margin = cfg.Reader.Margin
+		}
+	}
+	return r, r.loadTOC()
+}
+
+// openReaderAt is openReader restoring a saved position once the TOC
+// has loaded.
+func openReaderAt(svc *oreilly.Service, authSvc *auth.Service, pos progress.Position) (*reader, tea.Cmd) {
+	r, cmd := openReader(svc, authSvc, oreilly.Book{ProductID: pos.BookID, Title: pos.BookTitle})
+	r.resumeIdx = pos.ChapterIdx
+	r.resumeOffset = pos.Offset
+	return r, cmd
+}
+
+func (r *reader) loadTOC() tea.Cmd {
+	return func() tea.Msg {
+		jwt, err := r.authSvc.Token()
+		if err != nil {
+			return readerOpenedMsg{err: err}
+		}
+		toc, err := r.svc.FetchTOC(context.Background(), jwt, r.bookID)
+		if err != nil {
+			return readerOpenedMsg{err: err}
+		}
+		return readerOpenedMsg{chapters: oreilly.FlattenChapters(toc)}
+	}
+}
+
+// fetchChapter fetches and renders one chapter into the cache.
+func (r *reader) fetchChapter(idx int) tea.Cmd {
+	if idx < 0 || idx >= len(r.chapters) || r.cache[idx] != "" || r.pending[idx] {
+		return nil
+	}
+	r.pending[idx] = true
+	href := r.chapters[idx].Href
+	return func() tea.Msg {
+		jwt, err := r.authSvc.Token()
+		if err != nil {
+			return chapterMsg{idx: idx, err: err}
+		}
+		content, err := r.svc.FetchChapterContent(context.Background(), jwt, r.bookID, href)
+		if err != nil {
+			return chapterMsg{idx: idx, err: err}
+		}
+		text, code := htmlToText(string(sanitize.Chapter(content)))
+		return chapterMsg{idx: idx, text: text, code: code}
+	}
+}
+
+// showChapter displays chapter idx, fetching it if needed, and kicks off
+// prefetches for the next chapters.
+func (r *reader) showChapter(idx int) tea.Cmd {
+	if idx < 0 || idx >= len(r.chapters) {
+		return nil
+	}
+	r.idx = idx
+	r.codeIdx = 0
+	var cmds []tea.Cmd
+	if text, ok := r.cache[idx]; ok {
+		r.loading = false
+		r.setContent(text)
+		r.viewport.GotoTop()
+	} else {
+		r.loading = true
+		cmds = append(cmds, r.fetchChapter(idx))
+	}
+	for ahead := 1; ahead <= prefetchDepth; ahead++ {
+		if cmd := r.fetchChapter(idx + ahead); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+func (r *reader) setSize(width, height int) {
+	// Leave room for the header and footer lines.
+	r.width = width
+	r.viewport = viewport.New(width, height-4)
+	r.ready = true
+	if text, ok := r.cache[r.idx]; ok {
+		r.setContent(text)
+	}
+}
+
+// setContent renders chapter text into the viewport with the active
+// theme, line width, and margins applied.
+func (r *reader) setContent(text string) {
+	width := r.maxWidth
+	if r.width > 0 && width > r.width-2*r.margin {
+		width = r.width - 2*r.margin
+	}
+	if width < 20 {
+		width = 20
+	}
+	style := readerThemes[r.themeIdx].text.Copy().Width(width).PaddingLeft(r.margin)
+	r.viewport.SetContent(style.Render(text))
+}
+
+// update handles a message while the reader is open. It returns false
+// when the reader should close.
+func (r *reader) update(msg tea.Msg) (bool, tea.Cmd) {
+	if r.naming {
+		return true, r.updateNaming(msg)
+	}
+	if r.listing {
+		return r.updateListing(msg)
+	}
+	if r.tocListing {
+		return r.updateTocListing(msg)
+	}
+	if r.defPrompt {
+		return true, r.updateDefPrompt(msg)
+	}
+	if r.definition != "" {
+		if key, ok := msg.(tea.KeyMsg); ok {
+			switch key.String() {
+			case "esc", "q", "enter":
+				r.definition = ""
+			}
+		}
+		return true, nil
+	}
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc":
+			// esc first dismisses a lingering status message; q always
+			// leaves the reader so backing out never takes two presses.
+			if msg.String() == "esc" && r.statusMsg != "" {
+				r.statusMsg = ""
+				return true, nil
+			}
+			r.saveProgress()
+			return false, nil
+		case "right", "l", "n":
+			r.saveProgress()
+			return true, r.showChapter(r.idx + 1)
+		case "left", "h", "p":
+			r.saveProgress()
+			return true, r.showChapter(r.idx - 1)
+		case "b":
+			if len(r.chapters) > 0 {
+				r.naming = true
+				r.markInput = textinput.New()
+				r.markInput.Placeholder = "bookmark name"
+				r.markInput.CharLimit = 64
+				r.markInput.Focus()
+			}
+			return true, nil
+		case "T":
+			if len(r.chapters) > 0 {
+				r.tocListing = true
+				r.tocCursor = r.idx
+			}
+			return true, nil
+		case "D":
+			r.statusMsg = "downloading " + r.title + " in the background..."
+			return true, r.downloadBook()
+		case "t":
+			r.themeIdx = (r.themeIdx + 1) % len(readerThemes)
+			r.statusMsg = "theme: " + readerThemes[r.themeIdx].name
+			r.refreshContent()
+			return true, nil
+		case "+", "=":
+			r.maxWidth += 4
+			r.statusMsg = fmt.Sprintf("width: %d", r.maxWidth)
+			r.refreshContent()
+			return true, nil
+		case "-":
+			if r.maxWidth > 24 {
+				r.maxWidth -= 4
+			}
+			r.statusMsg = fmt.Sprintf("width: %d", r.maxWidth)
+			r.refreshContent()
+			return true, nil
+		case "]":
+			if blocks := r.codeBlocks[r.idx]; len(blocks) > 0 {
+				r.codeIdx = (r.codeIdx + 1) % len(blocks)
+				r.statusMsg = fmt.Sprintf("code block %d/%d selected", r.codeIdx+1, len(blocks))
+			}
+			return true, nil
+		case "[":
+			if blocks := r.codeBlocks[r.idx]; len(blocks) > 0 {
+				r.codeIdx = (r.codeIdx - 1 + len(blocks)) % len(blocks)
+				r.statusMsg = fmt.Sprintf("code block %d/%d selected", r.codeIdx+1, len(blocks))
+			}
+			return true, nil
+		case "c":
+			if block, ok := r.currentCode(); ok {
+				if err := util.CopyToClipboard(block); err != nil {
+					r.statusMsg = err.Error()
+				} else {
+					r.statusMsg = fmt.Sprintf("copied code block %d to clipboard", r.codeIdx+1)
+				}
+			}
+			return true, nil
+		case "x":
+			if block, ok := r.currentCode(); ok {
+				path, err := appendSnippet(r.title, r.chapters[r.idx].Title, block)
+				if err != nil {
+					r.statusMsg = err.Error()
+				} else {
+					r.statusMsg = "appended code block to " + path
+				}
+			}
+			return true, nil
+		case "d":
+			r.defPrompt = true
+			r.defInput = textinput.New()
+			r.defInput.Placeholder = "word to define"
+			r.defInput.CharLimit = 64
+			r.defInput.Focus()
+			return true, nil
+		case "B":
+			list, err := r.marks.ListForBook(r.bookID)
+			if err != nil {
+				r.statusMsg = "loading bookmarks: " + err.Error()
+				return true, nil
+			}
+			if len(list) == 0 {
+				r.statusMsg = "no bookmarks for this book"
+				return true, nil
+			}
+			r.markList = list
+			r.markCursor = 0
+			r.listing = true
+			return true, nil
+		}
+	case downloadDoneMsg:
+		if msg.err != nil {
+			r.statusMsg = "download failed: " + msg.err.Error()
+		} else {
+			r.statusMsg = "downloaded to " + msg.path
+		}
+		return true, nil
+	case definitionMsg:
+		if msg.err != nil {
+			r.statusMsg = msg.err.Error()
+			return true, nil
+		}
+		r.statusMsg = ""
+		r.definition = fmt.Sprintf("%s\n\n%s", titleStyle.Render(msg.word), strings.TrimSpace(msg.text))
+		return true, nil
+	case readerOpenedMsg:
+		r.loading = false
+		if msg.err != nil {
+			r.err = msg.err
+			return true, nil
+		}
+		r.chapters = msg.chapters
+		if len(r.chapters) == 0 {
+			r.err = fmt.Errorf("book has no chapters")
+			return true, nil
+		}
+		start := r.resumeIdx
+		if start >= len(r.chapters) {
+			start = 0
+			r.resumeOffset = 0
+		}
+		return true, r.showChapter(start)
+	case chapterMsg:
+		delete(r.pending, msg.idx)
+		if msg.err != nil {
+			// Prefetch failures are silent; the chapter is retried when
+			// actually opened.
+			if msg.idx == r.idx {
+				r.loading = false
+				r.err = msg.err
+			}
+			return true, nil
+		}
+		r.cache[msg.idx] = msg.text
+		r.codeBlocks[msg.idx] = msg.code
+		if msg.idx == r.idx {
+			r.loading = false
+			r.setContent(msg.text)
+			r.viewport.GotoTop()
+			if msg.idx == r.resumeIdx && r.resumeOffset > 0 {
+				r.viewport.SetYOffset(r.resumeOffset)
+				r.resumeOffset = 0
+			}
+		}
+		return true, nil
+	}
+	var cmd tea.Cmd
+	r.viewport, cmd = r.viewport.Update(msg)
+	return true, cmd
+}
+
+// downloadBook queues a full download of the open book in the
+// background, so "read a bit, decide, download" stays in the reader.
+func (r *reader) downloadBook() tea.Cmd {
+	bookID, title := r.bookID, r.title
+	svc, authSvc := r.svc, r.authSvc
+	return func() tea.Msg {
+		cfg, err := config.Load()
+		if err != nil {
+			return downloadDoneMsg{err: err}
+		}
+		jwt, err := authSvc.Token()
+		if err != nil {
+			return downloadDoneMsg{err: err}
+		}
+		path, _, err := downloader.Book(context.Background(), cfg, svc, jwt, bookID, cfg.OutputDir, nil)
+		if err != nil {
+			return downloadDoneMsg{err: fmt.Errorf("downloading %s: %w", title, err)}
+		}
+		return downloadDoneMsg{path: path}
+	}
+}
+
+// saveProgress records the open chapter and scroll offset in the local
+// progress store; failures are silent since losing a position is not
+// worth interrupting reading.
+func (r *reader) saveProgress() {
+	if len(r.chapters) == 0 {
+		return
+	}
+	_ = r.progress.Save(progress.Position{
+		BookID:     r.bookID,
+		BookTitle:  r.title,
+		Chapter:    r.chapters[r.idx].Title,
+		ChapterIdx: r.idx,
+		Offset:     r.viewport.YOffset,
+		Percent:    float64(r.idx+1) / float64(len(r.chapters)),
+	})
+}
+
+// refreshContent re-renders the open chapter after a presentation
+// change, keeping the scroll position.
+func (r *reader) refreshContent() {
+	if text, ok := r.cache[r.idx]; ok {
+		offset := r.viewport.YOffset
+		r.setContent(text)
+		r.viewport.SetYOffset(offset)
+	}
+}
+
+// currentCode returns the selected code block of the open chapter.
+func (r *reader) currentCode() (string, bool) {
+	blocks := r.codeBlocks[r.idx]
+	if len(blocks) == 0 {
+		r.statusMsg = "no code blocks in this chapter"
+		return "", false
+	}
+	return blocks[r.codeIdx], true
+}
+
+// appendSnippet appends a code block, with a provenance header, to the
+// scratch file and returns its path.
+func appendSnippet(book, chapter, block string) (string, error) {
+	path := filepath.Join(config.DataDir(), "snippets.txt")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := fmt.Fprintf(f, "# %s — %s\n%s\n\n", book, chapter, strings.TrimRight(block, "\n")); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// updateNaming handles keystrokes while typing a bookmark name.
+func (r *reader) updateNaming(msg tea.Msg) tea.Cmd {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.String() {
+		case "esc":
+			r.naming = false
+			return nil
+		case "enter":
+			name := strings.TrimSpace(r.markInput.Value())
+			if name == "" {
+				name = r.chapters[r.idx].Title
+			}
+			err := r.marks.Add(bookmarks.Bookmark{
+				Name:       name,
+				BookID:     r.bookID,
+				BookTitle:  r.title,
+				Chapter:    r.chapters[r.idx].Title,
+				ChapterIdx: r.idx,
+				Offset:     r.viewport.YOffset,
+			})
+			if err != nil {
+				r.statusMsg = "saving bookmark: " + err.Error()
+			} else {
+				r.statusMsg = "bookmarked " + name
+			}
+			r.naming = false
+			return nil
+		}
+	}
+	var cmd tea.Cmd
+	r.markInput, cmd = r.markInput.Update(msg)
+	return cmd
+}
+
+// updateDefPrompt handles keystrokes while typing a word to define.
+func (r *reader) updateDefPrompt(msg tea.Msg) tea.Cmd {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.String() {
+		case "esc":
+			r.defPrompt = false
+			return nil
+		case "enter":
+			word := strings.TrimSpace(r.defInput.Value())
+			r.defPrompt = false
+			if word == "" {
+				return nil
+			}
+			r.statusMsg = "looking up " + word + "..."
+			return func() tea.Msg {
+				text, err := dict.Lookup(context.Background(), word)
+				return definitionMsg{word: word, text: text, err: err}
+			}
+		}
+	}
+	var cmd tea.Cmd
+	r.defInput, cmd = r.defInput.Update(msg)
+	return cmd
+}
+
+// updateListing handles the bookmark list view.
+func (r *reader) updateListing(msg tea.Msg) (bool, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return true, nil
+	}
+	switch key.String() {
+	case "q", "esc":
+		r.listing = false
+	case "up", "k":
+		if r.markCursor > 0 {
+			r.markCursor--
+		}
+	case "down", "j":
+		if r.markCursor < len(r.markList)-1 {
+			r.markCursor++
+		}
+	case "d":
+		mark := r.markList[r.markCursor]
+		if err := r.marks.Remove(mark.BookID, mark.Name); err != nil {
+			r.statusMsg = "removing bookmark: " + err.Error()
+			return true, nil
+		}
+		r.markList = append(r.markList[:r.markCursor], r.markList[r.markCursor+1:]...)
+		if r.markCursor >= len(r.markList) && r.markCursor > 0 {
+			r.markCursor--
+		}
+		if len(r.markList) == 0 {
+			r.listing = false
+		}
+	case "enter":
+		mark := r.markList[r.markCursor]
+		r.listing = false
+		cmd := r.showChapter(mark.ChapterIdx)
+		if _, ok := r.cache[mark.ChapterIdx]; ok {
+			r.viewport.SetYOffset(mark.Offset)
+		}
+		return true, cmd
+	}
+	return true, nil
+}
+
+// updateTocListing handles the table-of-contents browser.
+func (r *reader) updateTocListing(msg tea.Msg) (bool, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return true, nil
+	}
+	switch key.String() {
+	case "q", "esc", "T":
+		r.tocListing = false
+	case "up", "k":
+		if r.tocCursor > 0 {
+			r.tocCursor--
+		}
+	case "down", "j":
+		if r.tocCursor < len(r.chapters)-1 {
+			r.tocCursor++
+		}
+	case "enter":
+		r.tocListing = false
+		r.saveProgress()
+		return true, r.showChapter(r.tocCursor)
+	}
+	return true, nil
+}
+
+// tocWindow is the slice of chapters to show around the cursor, so long
+// tables of contents never overflow the screen.
+func (r *reader) tocWindow() (int, int) {
+	rows := r.viewport.Height
+	if rows <= 0 || rows > len(r.chapters) {
+		return 0, len(r.chapters)
+	}
+	start := r.tocCursor - rows/2
+	if start < 0 {
+		start = 0
+	}
+	if start+rows > len(r.chapters) {
+		start = len(r.chapters) - rows
+	}
+	return start, start + rows
+}
+
+func (r *reader) view() string {
+	var b strings.Builder
+	header := r.title
+	if len(r.chapters) > 0 {
+		header = fmt.Sprintf("%s — %s (%d/%d)", r.title, r.chapters[r.idx].Title, r.idx+1, len(r.chapters))
+	}
+	b.WriteString(titleStyle.Render(header) + "\n")
+	switch {
+	case r.tocListing:
+		b.WriteString("Contents:\n")
+		start, end := r.tocWindow()
+		for i := start; i < end; i++ {
+			line := fmt.Sprintf("%3d. %s", i+1, r.chapters[i].Title)
+			if text := r.cache[i]; text != "" {
+				line += fmt.Sprintf("  ~%d min", oreilly.ReadingMinutes(len(strings.Fields(text))))
+			}
+			if i == r.idx {
+				line += "  (reading)"
+			}
+			if i == r.tocCursor {
+				line = selectedStyle.Render("> " + line)
+			} else {
+				line = "  " + line
+			}
+			b.WriteString(line + "\n")
+		}
+		b.WriteString(dimStyle.Render("enter: read chapter  esc: back") + "\n")
+		return b.String()
+	case r.listing:
+		b.WriteString("Bookmarks:\n")
+		for i, mark := range r.markList {
+			line := fmt.Sprintf("%s — %s", mark.Name, mark.Chapter)
+			if i == r.markCursor {
+				line = selectedStyle.Render("> " + line)
+			} else {
+				line = "  " + line
+			}
+			b.WriteString(line + "\n")
+		}
+		b.WriteString(dimStyle.Render("enter: jump  d: delete  esc: back") + "\n")
+		return b.String()
+	case r.definition != "":
+		b.WriteString(r.definition + "\n")
+		b.WriteString(dimStyle.Render("esc: close definition") + "\n")
+		return b.String()
+	case r.err != nil:
+		b.WriteString(errStyle.Render(r.err.Error()) + "\n")
+	case r.loading:
+		b.WriteString("Loading chapter...\n")
+	case r.ready:
+		b.WriteString(r.viewport.View() + "\n")
+	}
+	if r.naming {
+		b.WriteString("Bookmark name: " + r.markInput.View() + "\n")
+	}
+	if r.defPrompt {
+		b.WriteString("Define: " + r.defInput.View() + "\n")
+	}
+	if r.statusMsg != "" {
+		b.WriteString(dimStyle.Render(r.statusMsg) + "\n")
+	}
+	b.WriteString(dimStyle.Render("←/→: chapter  ↑/↓: scroll  T: contents  b/B: bookmarks  d: define  [/]/c/x: code  t: theme  +/-: width  q: back") + "\n")
+	return b.String()
+}
+
+// htmlToText renders chapter HTML to plain readable text, keeping block
+// structure but dropping markup. Code blocks (<pre>) are also returned
+// separately so they can be copied or exported verbatim.
+func htmlToText(html string) (string, []string) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return html, nil
+	}
+	var blocks []string
+	var code []string
+	doc.Find("h1, h2, h3, h4, h5, h6, p, pre, li, blockquote").Each(func(_ int, s *goquery.Selection) {
+		text := strings.TrimSpace(s.Text())
+		if text == "" {
+			return
+		}
+		blocks = append(blocks, text)
+		if s.Is("pre") {
+			code = append(code, s.Text())
+		}
+	})
+	if len(blocks) == 0 {
+		return strings.TrimSpace(doc.Text()), code
+	}
+	return strings.Join(blocks, "\n\n"), code
+}
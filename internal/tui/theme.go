@@ -0,0 +1,40 @@
+package tui
+
+import "github.com/charmbracelet/lipgloss"
+
+// readerTheme is a reading-mode color scheme, separate from the main
+// TUI styles so long sessions can use easier-on-the-eyes palettes.
+type readerTheme struct {
+	name string
+	text lipgloss.Style
+}
+
+// readerThemes are cycled with the theme key; the order matches what
+// the reader.theme config key accepts.
+var readerThemes = []readerTheme{
+	{name: "dark", text: lipgloss.NewStyle()},
+	{name: "light", text: lipgloss.NewStyle().Foreground(lipgloss.Color("235")).Background(lipgloss.Color("255"))},
+	{name: "sepia", text: lipgloss.NewStyle().Foreground(lipgloss.Color("58")).Background(lipgloss.Color("230"))},
+	{name: "high-contrast", text: lipgloss.NewStyle().Foreground(lipgloss.Color("15")).Background(lipgloss.Color("0")).Bold(true)},
+}
+
+// applyHighContrast swaps the app-wide styles for variants that stay
+// legible on low-vision setups and 16-color terminals: bold or reversed
+// text from the basic ANSI palette instead of dimmed 256-color shades.
+func applyHighContrast() {
+	titleStyle = lipgloss.NewStyle().Bold(true)
+	selectedStyle = lipgloss.NewStyle().Reverse(true).Bold(true)
+	dimStyle = lipgloss.NewStyle()
+	errStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Bold(true)
+}
+
+// themeIndex maps a reader.theme config value to its index, defaulting
+// to dark for unknown names.
+func themeIndex(name string) int {
+	for i, t := range readerThemes {
+		if t.name == name {
+			return i
+		}
+	}
+	return 0
+}
@@ -0,0 +1,33 @@
+package util
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// clipboardTools are tried in order; the first one on PATH wins.
+var clipboardTools = [][]string{
+	{"pbcopy"},
+	{"wl-copy"},
+	{"xclip", "-selection", "clipboard"},
+	{"xsel", "--input", "--clipboard"},
+}
+
+// CopyToClipboard writes text to the system clipboard via whichever
+// platform tool is installed.
+func CopyToClipboard(text string) error {
+	for _, tool := range clipboardTools {
+		path, err := exec.LookPath(tool[0])
+		if err != nil {
+			continue
+		}
+		cmd := exec.Command(path, tool[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s failed: %w", tool[0], err)
+		}
+		return nil
+	}
+	return fmt.Errorf("no clipboard tool found (tried pbcopy, wl-copy, xclip, xsel)")
+}
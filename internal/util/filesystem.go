@@ -0,0 +1,38 @@
+// Package util holds small shared helpers with no better home.
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var unsafeFilenameChars = regexp.MustCompile(`[<>:"/\\|?*\x00-\x1f]`)
+
+// FormatBytes renders a byte count human-readably (KiB/MiB/GiB).
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// SanitizeFilename makes a string safe to use as a file name on all
+// supported platforms.
+func SanitizeFilename(name string) string {
+	safe := unsafeFilenameChars.ReplaceAllString(name, "_")
+	safe = strings.Trim(safe, " .")
+	if len(safe) > 200 {
+		safe = safe[:200]
+	}
+	if safe == "" {
+		safe = "untitled"
+	}
+	return safe
+}
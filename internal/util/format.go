@@ -0,0 +1,47 @@
+package util
+
+import (
+	"fmt"
+	"time"
+)
+
+// FormatBytesDecimal renders a byte count with decimal units (kB/MB/GB),
+// for users who prefer SI sizes over the binary default.
+func FormatBytesDecimal(n int64) string {
+	const unit = 1000
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "kMGTPE"[exp])
+}
+
+// FormatBytesIn renders a byte count in the configured unit system:
+// "decimal" for SI units, anything else the binary default.
+func FormatBytesIn(n int64, units string) string {
+	if units == "decimal" {
+		return FormatBytesDecimal(n)
+	}
+	return FormatBytes(n)
+}
+
+// dateLayouts maps the format.dates config value to a layout; ISO is
+// the default.
+var dateLayouts = map[string]string{
+	"iso": "2006-01-02",
+	"us":  "Jan 2, 2006",
+	"eu":  "02.01.2006",
+}
+
+// FormatDate renders a date in the configured locale style.
+func FormatDate(t time.Time, style string) string {
+	layout, ok := dateLayouts[style]
+	if !ok {
+		layout = dateLayouts["iso"]
+	}
+	return t.Format(layout)
+}